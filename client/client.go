@@ -0,0 +1,191 @@
+// Package client queries the Adwords reports with the aawql driver
+// and maps each row of the result set on a struct.
+//
+// Columns are mapped to the struct fields by using the `awql` tag:
+//
+//	type CampaignRow struct {
+//		Name        string         `awql:"CampaignName"`
+//		Impressions int64          `awql:"Impressions"`
+//		Ctr         client.Percent `awql:"Ctr"`
+//		Cost        client.Money   `awql:"Cost"`
+//		Budget      *int64         `awql:"Amount"`
+//	}
+//
+//	var rows []CampaignRow
+//	err := c.Query(ctx, &rows, "SELECT CampaignName, Impressions, Ctr, Cost, Amount FROM CAMPAIGN_PERFORMANCE_REPORT")
+package client
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+
+	db "github.com/rvflash/awql-db"
+	parser "github.com/rvflash/awql-parser"
+	"github.com/rvflash/awql/driver"
+)
+
+// tagName is the name of the struct tag used to map a column.
+const tagName = "awql"
+
+// Client represents a client of the Adwords reports.
+type Client struct {
+	db     *sql.DB
+	schema *db.Database
+}
+
+// New returns an instance of Client.
+// Without the typed values option on the connection, the auto, excluded and percent markers are kept.
+// The schema is used to validate the column names before sending the query.
+func New(conn *sql.DB, schema *db.Database) *Client {
+	return &Client{db: conn, schema: schema}
+}
+
+// Open opens a connection with the aawql driver and uses the schema loaded by its connector.
+// The values are read as Google outputs them, the typed values option is disabled to keep their markers.
+// The configuration is copied, the one of the caller is not modified.
+func Open(cfg *driver.Config) (*Client, error) {
+	c := *cfg
+	c.TypedValues = false
+	cn, err := driver.NewConnector(&c)
+	if err != nil {
+		return nil, err
+	}
+	return New(sql.OpenDB(cn), cn.Database()), nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.db.Close()
+}
+
+// Query executes a query and appends each row of the result set in the slice pointed by dest.
+// The elements of the slice must be structs or pointers to structs.
+// The names of the columns used by the struct are validated against the schema before sending the query.
+func (c *Client) Query(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	// Checks the kind of destination.
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return ErrDestination
+	}
+	slice := rv.Elem()
+	et := slice.Type().Elem()
+	st := et
+	if et.Kind() == reflect.Ptr {
+		st = et.Elem()
+	}
+	if st.Kind() != reflect.Struct {
+		return ErrDestination
+	}
+	fields := structFields(st)
+
+	// Validates the column names used by the struct.
+	if err := c.validate(query, fields); err != nil {
+		return err
+	}
+
+	// Sends the query.
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	// Maps each column of the result set with a struct's field.
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	index := make([][]int, len(cols))
+	for i, name := range cols {
+		var ok bool
+		if index[i], ok = fields[strings.TrimSpace(name)]; !ok {
+			return NewXError("missing destination", name)
+		}
+	}
+	vals := make([]interface{}, len(cols))
+	ptrs := make([]interface{}, len(cols))
+	for i := range ptrs {
+		ptrs[i] = &vals[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		row := reflect.New(st).Elem()
+		for i, v := range vals {
+			if err := assign(row.FieldByIndex(index[i]), v); err != nil {
+				return NewXError(err.Error(), cols[i])
+			}
+		}
+		if et.Kind() == reflect.Ptr {
+			slice.Set(reflect.Append(slice, row.Addr()))
+		} else {
+			slice.Set(reflect.Append(slice, row))
+		}
+	}
+	return rows.Err()
+}
+
+// validate checks that each column used by the struct exists in the table of the query.
+// The aliases defined in the query are also accepted.
+func (c *Client) validate(query string, fields map[string][]int) error {
+	table, aliases := scanQuery(query)
+	if table == "" {
+		return ErrNoTable
+	}
+	t, err := c.schema.Table(table)
+	if err != nil {
+		return NewXError(err.Error(), table)
+	}
+	for name := range fields {
+		if aliases[name] {
+			continue
+		}
+		if _, err := t.Field(name); err != nil {
+			return NewXError(err.Error(), name)
+		}
+	}
+	return nil
+}
+
+// scanQuery returns the name of the table used by the query and the list of aliases declared on its columns.
+func scanQuery(query string) (table string, aliases map[string]bool) {
+	aliases = make(map[string]bool)
+	s := parser.NewScanner(strings.NewReader(query))
+	var prev parser.Token
+	for {
+		tk, literal := s.Scan()
+		switch tk {
+		case parser.EOF:
+			return
+		case parser.WHITE_SPACE:
+			continue
+		case parser.IDENTIFIER:
+			switch prev {
+			case parser.AS:
+				aliases[literal] = true
+			case parser.FROM:
+				if table == "" {
+					table = literal
+				}
+			}
+		}
+		prev = tk
+	}
+}
+
+// structFields returns for each column name, the index of the struct's field to use.
+func structFields(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := f.Tag.Get(tagName)
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = f.Index
+	}
+	return fields
+}
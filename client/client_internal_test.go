@@ -0,0 +1,143 @@
+package client
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rvflash/awql/driver"
+)
+
+type row struct {
+	Name     string     `awql:"CampaignName"`
+	Clicks   int64      `awql:"Clicks"`
+	Ctr      float64    `awql:"Ctr"`
+	Budget   *int64     `awql:"Amount"`
+	Day      time.Time  `awql:"Date"`
+	Cost     Money      `awql:"Cost"`
+	Share    Percent    `awql:"SearchImpressionShare"`
+	Seen     *time.Time `awql:"-"`
+	Internal string
+}
+
+// TestAssign tests the function named assign.
+func TestAssign(t *testing.T) {
+	var (
+		budget = int64(3000000)
+		day    = time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC)
+	)
+	var assignTests = []struct {
+		field string
+		src   interface{}
+		out   interface{}
+		err   bool
+	}{
+		// Typed values.
+		{field: "Name", src: nil, out: ""},
+		{field: "Name", src: "Campaign #1", out: "Campaign #1"},
+		{field: "Clicks", src: int64(12), out: int64(12)},
+		{field: "Clicks", src: float64(12), out: int64(12)},
+		{field: "Ctr", src: 0.25, out: 0.25},
+		{field: "Budget", src: nil, out: (*int64)(nil)},
+		{field: "Budget", src: budget, out: &budget},
+		{field: "Day", src: day, out: day},
+		{field: "Cost", src: int64(1000000), out: Money(1000000)},
+		// Values as Google outputs them.
+		{field: "Name", src: " --", out: ""},
+		{field: "Name", src: "auto", out: "auto"},
+		{field: "Clicks", src: "12", out: int64(12)},
+		{field: "Clicks", src: "12.00", out: int64(12)},
+		{field: "Clicks", src: "Excluded", out: int64(0)},
+		{field: "Ctr", src: "25.00%", out: 25.0},
+		{field: "Ctr", src: "auto: 0.25", out: 0.25},
+		{field: "Budget", src: "auto", out: (*int64)(nil)},
+		{field: "Budget", src: "auto: 3000000", out: &budget},
+		{field: "Day", src: "2018-01-31", out: day},
+		{field: "Share", src: "> 90%", out: Percent{Float64: 90, Above: true, Valid: true}},
+		// Errors.
+		{field: "Clicks", src: "Oops", err: true},
+		{field: "Clicks", src: true, err: true},
+		{field: "Day", src: "31/01/2018", err: true},
+		{field: "Cost", src: " --", err: true},
+	}
+	for i, tt := range assignTests {
+		var r row
+		f := reflect.ValueOf(&r).Elem().FieldByName(tt.field)
+		if err := assign(f, tt.src); tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if !tt.err && !reflect.DeepEqual(f.Interface(), tt.out) {
+			t.Errorf("%d. Expected %v, received %v", i, tt.out, f.Interface())
+		}
+	}
+}
+
+// TestStructFields tests the function named structFields.
+func TestStructFields(t *testing.T) {
+	out := map[string][]int{
+		"CampaignName": {0}, "Clicks": {1}, "Ctr": {2}, "Amount": {3}, "Date": {4}, "Cost": {5}, "SearchImpressionShare": {6},
+	}
+	if fields := structFields(reflect.TypeOf(row{})); !reflect.DeepEqual(fields, out) {
+		t.Errorf("Expected %v, received %v", out, fields)
+	}
+}
+
+// TestScanQuery tests the function named scanQuery.
+func TestScanQuery(t *testing.T) {
+	var queryTests = []struct {
+		query   string
+		table   string
+		aliases map[string]bool
+	}{
+		{query: "", aliases: map[string]bool{}},
+		{query: "SHOW TABLES", aliases: map[string]bool{}},
+		{
+			query:   "SELECT CampaignName, Cost FROM CAMPAIGN_PERFORMANCE_REPORT",
+			table:   "CAMPAIGN_PERFORMANCE_REPORT",
+			aliases: map[string]bool{},
+		},
+		{
+			query:   "SELECT CampaignName AS Name, SUM(Cost) AS Spend FROM CAMPAIGN_PERFORMANCE_REPORT GROUP BY 1",
+			table:   "CAMPAIGN_PERFORMANCE_REPORT",
+			aliases: map[string]bool{"Name": true, "Spend": true},
+		},
+	}
+	for i, tt := range queryTests {
+		table, aliases := scanQuery(tt.query)
+		if table != tt.table {
+			t.Errorf("%d. Expected table %v, received %v", i, tt.table, table)
+		}
+		if !reflect.DeepEqual(aliases, tt.aliases) {
+			t.Errorf("%d. Expected aliases %v, received %v", i, tt.aliases, aliases)
+		}
+	}
+}
+
+// TestOpen tests the function named Open.
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := driver.NewConfig("123-456-7890")
+	cfg.DatabaseDir = dir
+	cfg.CacheBackend = driver.CacheNone
+	cfg.TypedValues = true
+	c, err := Open(cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	defer c.Close()
+	if !cfg.TypedValues {
+		t.Error("Expected the typed values option of the caller kept, received disabled")
+	}
+	if c.schema == nil {
+		t.Fatal("Expected the schema of the connector, received nil")
+	}
+	if _, err := c.schema.Table("CAMPAIGN_PERFORMANCE_REPORT"); err != nil {
+		t.Errorf("Expected no error, received %s", err)
+	}
+}
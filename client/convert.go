@@ -0,0 +1,322 @@
+package client
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Generic patterns in Google reports.
+const (
+	// Google can prefix a value by `auto:` or just return `auto` to symbolize an automatic strategy.
+	auto      = "auto"
+	autoValue = auto + ": "
+
+	// Google uses `Excluded` to tag null value by context.
+	excluded = "Excluded"
+
+	// Google uses ` --` instead of an empty string to symbolize the fact that the field was never set.
+	doubleDash = " --"
+
+	// Google sometimes uses special value like `> 90%` or `< 10%`.
+	above   = "> "
+	below   = "< "
+	percent = "%"
+
+	// Layouts used by Google to display the dates.
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006/01/02 15:04:05"
+)
+
+var (
+	errConvert  = errors.New("unsupported conversion")
+	errNull     = errors.New("null value")
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// Money represents an amount in micros, as returned by Adwords for the Money columns.
+// It can not be null, NullMoney or a pointer must be used for the nullable columns.
+type Money int64
+
+// Float64 returns the amount in the currency unit.
+func (m Money) Float64() float64 {
+	return float64(m) / 1000000
+}
+
+// String returns the amount in the currency unit with 2 decimals.
+// It implements the fmt.Stringer interface.
+func (m Money) String() string {
+	return strconv.FormatFloat(m.Float64(), 'f', 2, 64)
+}
+
+// Scan implements the sql.Scanner interface.
+// A null value returns an error, in order to not confuse it with a zero amount.
+func (m *Money) Scan(src interface{}) error {
+	var a Auto
+	if err := a.Scan(src); err != nil {
+		return err
+	}
+	if !a.Valid {
+		return errNull
+	}
+	*m = Money(a.Int64)
+	return nil
+}
+
+// NullMoney represents an amount in micros that may be null.
+type NullMoney struct {
+	Money Money
+	Valid bool // Valid is true if Money is not NULL
+}
+
+// Scan implements the sql.Scanner interface.
+func (n *NullMoney) Scan(src interface{}) error {
+	var a Auto
+	if err := a.Scan(src); err != nil {
+		return err
+	}
+	n.Money, n.Valid = Money(a.Int64), a.Valid
+	return nil
+}
+
+// Percent represents a percentage as returned by Adwords, like 12.34% for 12.34.
+// Google only gives a bound for some values, as `> 90%` or `< 10%`.
+type Percent struct {
+	Float64 float64
+	Above,
+	Below,
+	Valid bool // Valid is true if Float64 is not NULL
+}
+
+// Scan implements the sql.Scanner interface.
+func (p *Percent) Scan(src interface{}) error {
+	*p = Percent{}
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case int64:
+		p.Float64, p.Valid = float64(v), true
+		return nil
+	case float64:
+		p.Float64, p.Valid = v, true
+		return nil
+	case []byte:
+		src = string(v)
+	}
+	s, ok := src.(string)
+	if !ok {
+		return errConvert
+	}
+	if isNull(s) {
+		return nil
+	}
+	switch {
+	case strings.HasPrefix(s, above):
+		p.Above, s = true, strings.TrimPrefix(s, above)
+	case strings.HasPrefix(s, below):
+		p.Below, s = true, strings.TrimPrefix(s, below)
+	}
+	f, err := strconv.ParseFloat(strings.TrimSuffix(s, percent), 64)
+	if err != nil {
+		return errConvert
+	}
+	p.Float64, p.Valid = f, true
+	return nil
+}
+
+// Auto represents an integer that may be set automatically by Google, as `auto: 123` or `auto`,
+// or excluded by the context of the report.
+type Auto struct {
+	Int64 int64
+	Auto,
+	Excluded,
+	Valid bool // Valid is true if Int64 is not NULL, nor excluded or automatic without value.
+}
+
+// Scan implements the sql.Scanner interface.
+func (a *Auto) Scan(src interface{}) error {
+	*a = Auto{}
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case int64:
+		a.Int64, a.Valid = v, true
+		return nil
+	case float64:
+		a.Int64, a.Valid = int64(v), true
+		return nil
+	case []byte:
+		src = string(v)
+	}
+	s, ok := src.(string)
+	if !ok {
+		return errConvert
+	}
+	switch {
+	case s == excluded:
+		a.Excluded = true
+		return nil
+	case s == auto:
+		a.Auto = true
+		return nil
+	case strings.HasPrefix(s, autoValue):
+		a.Auto, s = true, strings.TrimPrefix(s, autoValue)
+	case isNull(s):
+		return nil
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return errConvert
+	}
+	a.Int64, a.Valid = i, true
+	return nil
+}
+
+// assign sets the value returned by the driver on the struct's field.
+// The value is a Go type with the typed values option, the string displayed by Google otherwise.
+// Null values set the zero value of the field, or nil on a pointer.
+func assign(f reflect.Value, v interface{}) error {
+	// The field knows how to scan the value.
+	if f.CanAddr() && f.Addr().Type().Implements(scannerType) {
+		return f.Addr().Interface().(sql.Scanner).Scan(v)
+	}
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	if s, ok := v.(string); ok {
+		// Excluded or automatic without value, a number is null, but not a string.
+		t := f.Type()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if isNull(s) || (t.Kind() != reflect.String && (s == excluded || s == auto)) {
+			v = nil
+		}
+	}
+	if v == nil {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+	if f.Kind() == reflect.Ptr {
+		p := reflect.New(f.Type().Elem())
+		if err := assign(p.Elem(), v); err != nil {
+			return err
+		}
+		f.Set(p)
+		return nil
+	}
+	if f.Type() == timeType {
+		t, err := parseTime(v)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := parseInt(v)
+		if err != nil {
+			return err
+		}
+		f.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := parseInt(v)
+		if err != nil || i < 0 {
+			return errConvert
+		}
+		f.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		d, err := parseFloat(v)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(d)
+	case reflect.Bool:
+		s, ok := v.(string)
+		if !ok {
+			return errConvert
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return errConvert
+		}
+		f.SetBool(b)
+	case reflect.String:
+		switch t := v.(type) {
+		case string:
+			f.SetString(t)
+		case int64:
+			f.SetString(strconv.FormatInt(t, 10))
+		case float64:
+			f.SetString(strconv.FormatFloat(t, 'f', -1, 64))
+		case time.Time:
+			f.SetString(t.Format(dateLayout))
+		default:
+			return errConvert
+		}
+	default:
+		return errConvert
+	}
+	return nil
+}
+
+// isNull returns true if the string is the representation of a null value by Google.
+func isNull(s string) bool {
+	return s == doubleDash || s == ""
+}
+
+// parseFloat returns the value as float64, without its percent sign or its automatic prefix.
+func parseFloat(v interface{}) (float64, error) {
+	switch t := v.(type) {
+	case int64:
+		return float64(t), nil
+	case float64:
+		return t, nil
+	case string:
+		var p Percent
+		if err := p.Scan(strings.TrimPrefix(t, autoValue)); err != nil || !p.Valid {
+			return 0, errConvert
+		}
+		return p.Float64, nil
+	}
+	return 0, errConvert
+}
+
+// parseInt returns the value as int64, without its automatic prefix or its decimals.
+func parseInt(v interface{}) (int64, error) {
+	switch t := v.(type) {
+	case int64:
+		return t, nil
+	case float64:
+		return int64(t), nil
+	case string:
+		var a Auto
+		if err := a.Scan(t); err == nil && a.Valid {
+			return a.Int64, nil
+		}
+		// The aggregated values are floats.
+		f, err := parseFloat(t)
+		return int64(f), err
+	}
+	return 0, errConvert
+}
+
+// parseTime returns the value as time.Time, the strings being a date or a date time as Google outputs them.
+func parseTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		for _, layout := range []string{dateLayout, dateTimeLayout} {
+			if d, err := time.Parse(layout, t); err == nil {
+				return d, nil
+			}
+		}
+	}
+	return time.Time{}, errConvert
+}
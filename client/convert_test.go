@@ -0,0 +1,123 @@
+package client_test
+
+import (
+	"testing"
+
+	"github.com/rvflash/awql/client"
+)
+
+// TestMoney_Scan tests the method named Scan on Money type.
+func TestMoney_Scan(t *testing.T) {
+	var moneyTests = []struct {
+		src interface{}
+		out client.Money
+		err bool
+	}{
+		{src: nil, err: true},
+		{src: " --", err: true},
+		{src: "Excluded", err: true},
+		{src: "auto", err: true},
+		{src: "Oops", err: true},
+		{src: true, err: true},
+		{src: int64(0), out: 0},
+		{src: int64(1230000), out: 1230000},
+		{src: float64(1230000), out: 1230000},
+		{src: "1230000", out: 1230000},
+		{src: []byte("1230000"), out: 1230000},
+		{src: "auto: 1230000", out: 1230000},
+	}
+	for i, tt := range moneyTests {
+		var m client.Money
+		if err := m.Scan(tt.src); tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if !tt.err && m != tt.out {
+			t.Errorf("%d. Expected %v, received %v", i, tt.out, m)
+		}
+	}
+}
+
+// TestMoney_String tests the method named String on Money type.
+func TestMoney_String(t *testing.T) {
+	if s := client.Money(1234567).String(); s != "1.23" {
+		t.Errorf("Expected 1.23, received %v", s)
+	}
+}
+
+// TestNullMoney_Scan tests the method named Scan on NullMoney struct.
+func TestNullMoney_Scan(t *testing.T) {
+	var moneyTests = []struct {
+		src interface{}
+		out client.NullMoney
+		err bool
+	}{
+		{src: nil},
+		{src: " --"},
+		{src: "Excluded"},
+		{src: "Oops", err: true},
+		{src: int64(0), out: client.NullMoney{Valid: true}},
+		{src: "0", out: client.NullMoney{Valid: true}},
+		{src: "1230000", out: client.NullMoney{Money: 1230000, Valid: true}},
+	}
+	for i, tt := range moneyTests {
+		var m client.NullMoney
+		if err := m.Scan(tt.src); tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if !tt.err && m != tt.out {
+			t.Errorf("%d. Expected %v, received %v", i, tt.out, m)
+		}
+	}
+}
+
+// TestPercent_Scan tests the method named Scan on Percent struct.
+func TestPercent_Scan(t *testing.T) {
+	var percentTests = []struct {
+		src interface{}
+		out client.Percent
+		err bool
+	}{
+		{src: nil},
+		{src: " --"},
+		{src: "12.34 %", err: true},
+		{src: "Oops%", err: true},
+		{src: int64(12), out: client.Percent{Float64: 12, Valid: true}},
+		{src: 12.34, out: client.Percent{Float64: 12.34, Valid: true}},
+		{src: "12.34", out: client.Percent{Float64: 12.34, Valid: true}},
+		{src: "12.34%", out: client.Percent{Float64: 12.34, Valid: true}},
+		{src: "> 90%", out: client.Percent{Float64: 90, Above: true, Valid: true}},
+		{src: "< 10%", out: client.Percent{Float64: 10, Below: true, Valid: true}},
+	}
+	for i, tt := range percentTests {
+		var p client.Percent
+		if err := p.Scan(tt.src); tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if !tt.err && p != tt.out {
+			t.Errorf("%d. Expected %v, received %v", i, tt.out, p)
+		}
+	}
+}
+
+// TestAuto_Scan tests the method named Scan on Auto struct.
+func TestAuto_Scan(t *testing.T) {
+	var autoTests = []struct {
+		src interface{}
+		out client.Auto
+		err bool
+	}{
+		{src: nil},
+		{src: " --"},
+		{src: "auto: Oops", err: true},
+		{src: "Excluded", out: client.Auto{Excluded: true}},
+		{src: "auto", out: client.Auto{Auto: true}},
+		{src: "auto: 123", out: client.Auto{Int64: 123, Auto: true, Valid: true}},
+		{src: "123", out: client.Auto{Int64: 123, Valid: true}},
+		{src: int64(123), out: client.Auto{Int64: 123, Valid: true}},
+	}
+	for i, tt := range autoTests {
+		var a client.Auto
+		if err := a.Scan(tt.src); tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if !tt.err && a != tt.out {
+			t.Errorf("%d. Expected %v, received %v", i, tt.out, a)
+		}
+	}
+}
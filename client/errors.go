@@ -0,0 +1,42 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error messages.
+var (
+	ErrDestination = NewError("destination must be a pointer to a slice of structs")
+	ErrNoTable     = NewError("missing source")
+)
+
+// Error represents a client error.
+type Error struct {
+	s string
+	a interface{}
+}
+
+// NewError returns an error of type Client with the given text.
+func NewError(text string) error {
+	return &Error{s: formatError(text)}
+}
+
+// NewXError returns an error of type Client with the given text and more information about it.
+func NewXError(text string, arg interface{}) error {
+	return &Error{s: formatError(text), a: arg}
+}
+
+// Error outputs a client error message.
+func (e *Error) Error() string {
+	if e.a != nil {
+		return fmt.Sprintf("ClientError.%v (%v)", e.s, e.a)
+	}
+	return "ClientError." + e.s
+}
+
+// formatError returns a string in upper case with underscore instead of space.
+// As the Adwords API outputs its errors.
+func formatError(s string) string {
+	return strings.Replace(strings.ToUpper(strings.TrimSpace(s)), " ", "_", -1)
+}
@@ -1,13 +1,15 @@
 package conf
 
 import (
+	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
+	"sort"
 
 	db "github.com/rvflash/awql-db"
-	awql "github.com/rvflash/awql-driver"
 	"github.com/rvflash/awql/driver"
+	"gopkg.in/yaml.v2"
 )
 
 // Options represents all available parameters.
@@ -18,6 +20,8 @@ type Options interface {
 	IsInteractive() bool
 	SupportsZeroImpressions() bool
 	UseBatchMode() bool
+	UseRawEnumValues() bool
+	IncludeReportSummary() bool
 	UseVerboseMode() bool
 	WithAutoRehash() bool
 	WithCache() bool
@@ -27,10 +31,23 @@ type Options interface {
 type Settings interface {
 	Options
 	Dsn() string
+	Database() (*db.Database, error)
 	CacheDir() string
+	CatalogFile() string
 	DatabaseDir() string
 	HistoryFile() string
+	SchemaDir() string
+	ViewsFile() string
 	Init() error
+	Accounts() ([]Account, error)
+	SetAccount(name string) error
+	SetAPIVersion(version string) error
+	SetCache(enable bool)
+}
+
+// Account represents an Adwords account, with its alias in the accounts file.
+type Account struct {
+	ID, Alias string
 }
 
 // Context represents the program properties.
@@ -51,6 +68,66 @@ func (c *Context) AccountID() string {
 	return *c.opts.AccountID
 }
 
+// Accounts returns the accounts of the accounts file, with the current one.
+// They are sorted by alias, the accounts without alias are listed after.
+func (c *Context) Accounts() ([]Account, error) {
+	aliases, err := c.aliases()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		list    []Account
+		current bool
+	)
+	for alias, id := range aliases {
+		list = append(list, Account{ID: id, Alias: alias})
+		current = current || id == c.AccountID()
+	}
+	if !current {
+		list = append(list, Account{ID: c.AccountID()})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if (list[i].Alias == "") != (list[j].Alias == "") {
+			return list[j].Alias == ""
+		}
+		if list[i].Alias != list[j].Alias {
+			return list[i].Alias < list[j].Alias
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+// AccountsFile returns the path to the file of the accounts by alias.
+func (c *Context) AccountsFile() string {
+	if c.homeDir == "" {
+		return ""
+	}
+	return filepath.Join(c.homeDir, "accounts.yml")
+}
+
+// SetAccount changes the account, given by its ID or by its alias in the accounts file.
+// The history of the account starts with the legacy one, shared by all the accounts with the previous releases.
+func (c *Context) SetAccount(name string) error {
+	id := name
+	if !isAccountID(id) {
+		aliases, err := c.aliases()
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if id, ok = aliases[name]; !ok {
+			return NewFlagError(UsageAccountID)
+		}
+	}
+	if err := c.importHistory(id); err != nil {
+		return err
+	}
+	*c.opts.AccountID = id
+
+	return nil
+}
+
 // APIVersion returns the API version.
 func (c *Context) APIVersion() string {
 	return *c.opts.APIVersion
@@ -64,7 +141,18 @@ func (c *Context) CacheDir() string {
 	return filepath.Join(c.homeDir, "cache")
 }
 
+// CatalogFile returns the path to the read-only catalog of views.
+func (c *Context) CatalogFile() string {
+	return *c.opts.Catalog
+}
+
+// Database returns the database of the API version, with the views of the user and of the catalog.
+func (c *Context) Database() (*db.Database, error) {
+	return c.openDatabase(true)
+}
+
 // DatabaseDir returns the path to the database.
+// Since the views are stored in the home directory, it is only used to retrieve the legacy views file.
 func (c *Context) DatabaseDir() string {
 	return filepath.Join(c.wrkDir, "vendor/github.com/rvflash/awql-db/internal/schema/src")
 }
@@ -72,21 +160,42 @@ func (c *Context) DatabaseDir() string {
 // Dsn outputs the data source name.
 func (c *Context) Dsn() string {
 	// Data source name used to connect to Adwords.
-	dsn := awql.NewDsn(c.AccountID())
-	dsn.APIVersion = c.APIVersion()
-	dsn.SupportsZeroImpressions = c.SupportsZeroImpressions()
-	dsn.SkipColumnHeader = true
+	cfg := driver.NewConfig(c.AccountID())
+	cfg.APIVersion = c.APIVersion()
+	cfg.SupportsZeroImpressions = c.SupportsZeroImpressions()
+	cfg.UseRawEnumValues = c.UseRawEnumValues()
+	cfg.IncludeReportSummary = c.IncludeReportSummary()
+	cfg.DatabaseDir = c.DatabaseDir()
+	cfg.ViewsFile = c.ViewsFile()
+	cfg.CatalogFile = c.CatalogFile()
+	cfg.SchemaDir = c.SchemaDir()
+	cfg.CacheDir = c.CacheDir()
+	cfg.WithCache = c.WithCache()
+
+	// Google Ads API as backend.
+	if *c.opts.GoogleAds {
+		cfg.Backend = driver.BackendGoogleAds
+	}
+	cfg.AdsEndpoint = *c.opts.AdsEndpoint
+	cfg.AdsVersion = *c.opts.AdsVersion
+	cfg.LoginCustomerID = *c.opts.LoginCustomerID
 
 	// Credentials.
-	dsn.AccessToken = c.tk.AccessToken
-	dsn.ClientID = c.tk.ClientID
-	dsn.ClientSecret = c.tk.ClientSecret
-	dsn.DeveloperToken = c.tk.DeveloperToken
-	dsn.RefreshToken = c.tk.RefreshToken
+	cfg.AccessToken = c.tk.AccessToken
+	cfg.ClientID = c.tk.ClientID
+	cfg.ClientSecret = c.tk.ClientSecret
+	cfg.DeveloperToken = c.tk.DeveloperToken
+	cfg.RefreshToken = c.tk.RefreshToken
 
-	return driver.NewDsn(
-		c.DatabaseDir(), dsn.String(), c.CacheDir(), c.WithCache(),
-	).String()
+	// The shell keeps the values as Google outputs them, with the auto, excluded and percent markers.
+	cfg.TypedValues = false
+
+	return driver.FormatDSN(cfg)
+}
+
+// DocsDir returns the path to the directory of the documentation to generate.
+func (c *Context) DocsDir() string {
+	return *c.opts.Docs
 }
 
 // ExecuteStmt returns the statement to execute.
@@ -94,27 +203,155 @@ func (c *Context) ExecuteStmt() string {
 	return *c.opts.Query
 }
 
-// HistoryFile returns the path to the history file.
+// HistoryFile returns the path to the history file of the account.
 func (c *Context) HistoryFile() string {
+	return c.historyFile(c.AccountID())
+}
+
+// historyFile returns the path to the history file of this account.
+func (c *Context) historyFile(id string) string {
+	if c.homeDir == "" {
+		return ""
+	}
+	return filepath.Join(c.homeDir, "history-"+id)
+}
+
+// SchemaDir returns the path to the directory of the schemas of the API versions.
+// Without option, the schema directory of the home directory is used.
+func (c *Context) SchemaDir() string {
+	if *c.opts.SchemaDir != "" || c.homeDir == "" {
+		return *c.opts.SchemaDir
+	}
+	return filepath.Join(c.homeDir, "schema")
+}
+
+// SchemaFile returns the path to the schema file to check.
+func (c *Context) SchemaFile() string {
+	return *c.opts.CheckSchema
+}
+
+// CheckSchema checks the structure of the schema file.
+func (c *Context) CheckSchema() error {
+	buf, err := ioutil.ReadFile(c.SchemaFile())
+	if err != nil {
+		return err
+	}
+	return db.CheckSchema(buf)
+}
+
+// SchemaChanges returns the changes of the reports from the version of the API given as option
+// to the one in use, only for one table if it is also given.
+// The embedded versions and these of the schema directory can be compared.
+func (c *Context) SchemaChanges() ([]db.Change, error) {
+	if !isAPIVersion(c.ChangesVersion()) {
+		return nil, NewFlagError(UsageSchemaChanges)
+	}
+	if !isAPIVersion(c.APIVersion()) {
+		return nil, NewFlagError(UsageAPIVersion)
+	}
+	// Uses the schema directory of the home directory by default.
+	if err := c.mkDirHome(); err != nil {
+		return nil, err
+	}
+	d, err := db.OpenConfig(c.databaseConfig(c.APIVersion(), false))
+	if err != nil {
+		return nil, err
+	}
+	from, err := d.Schema(c.ChangesVersion())
+	if err != nil {
+		return nil, err
+	}
+	to, err := d.Schema(c.APIVersion())
+	if err != nil {
+		return nil, err
+	}
+	return from.SchemaChanges(to, c.ChangesTable())
+}
+
+// ChangesVersion returns the version of the API to compare with the one in use.
+func (c *Context) ChangesVersion() string {
+	return *c.opts.SchemaChanges
+}
+
+// ChangesTable returns the only table to compare between the two versions of the API.
+func (c *Context) ChangesTable() string {
+	return *c.opts.Table
+}
+
+// RenameColumnsFile returns the path to the file of the columns to rename in the views.
+func (c *Context) RenameColumnsFile() string {
+	return *c.opts.RenameColumns
+}
+
+// WithViewsCheck returns true if the views are only checked against the API version.
+func (c *Context) WithViewsCheck() bool {
+	return *c.opts.CheckViews
+}
+
+// CheckViews checks the views of the user and of the catalog against the version of the API.
+// With a file of columns to rename, the views of the user are first rewritten with the new names.
+// It returns the number of views rewritten and the problems of the views.
+func (c *Context) CheckViews() (int, []db.Issue, error) {
+	if !isAPIVersion(c.APIVersion()) {
+		return 0, nil, NewFlagError(UsageAPIVersion)
+	}
+	d, err := c.openDatabase(false)
+	if err != nil {
+		return 0, nil, err
+	}
+	var n int
+	if f := c.RenameColumnsFile(); f != "" {
+		buf, err := ioutil.ReadFile(f)
+		if err != nil {
+			return 0, nil, err
+		}
+		var names map[string]string
+		if err := yaml.Unmarshal(buf, &names); err != nil {
+			return 0, nil, NewFlagError(UsageRenameColumns)
+		}
+		if n, err = d.RenameViewColumns(names); err != nil {
+			return 0, nil, err
+		}
+	}
+	issues, err := d.CheckViews(c.APIVersion())
+
+	return n, issues, err
+}
+
+// ViewsFile returns the path to the views file of the user.
+func (c *Context) ViewsFile() string {
 	if c.homeDir == "" {
 		return ""
 	}
-	return filepath.Join(c.homeDir, "history")
+	return filepath.Join(c.homeDir, "views.yml")
 }
 
 // Init retrieves and saves the default authenticate information.
+// With a tool on the schema or the views, only the home directory and the views are prepared.
 func (c *Context) Init() error {
 	// Checks for required flags.
 	if err := c.opts.Check(); err != nil {
 		return err
 	}
-	// Checks if it's a supported Adwords API versions.
+	// Create the tool home directory.
+	if err := c.mkDirHome(); err != nil {
+		return err
+	}
+	// Checks if it's a supported Adwords API versions, embedded or in the schema directory.
 	// Opens a connection to Awql DB without load anything.
-	if _, err := db.Open(c.APIVersion() + ":true"); err != nil {
+	if _, err := db.OpenConfig(c.databaseConfig(c.APIVersion(), false)); err != nil {
 		return err
 	}
-	// Create the tool home directory.
-	if err := c.mkDirHome(); err != nil {
+	// Moves the views created in the source tree by the previous releases.
+	if err := c.importViews(); err != nil {
+		return err
+	}
+	// The tools do not connect to Adwords.
+	if c.opts.UseTool() {
+		return nil
+	}
+	// Starts the history of the account with the one shared by all the accounts with the previous releases.
+	if err := c.importHistory(c.AccountID()); err != nil {
 		return err
 	}
 	// Checks credential to authenticate to Adwords.
@@ -138,6 +375,29 @@ func (c *Context) Init() error {
 	return nil
 }
 
+// SetAPIVersion changes the API version, if it is supported, embedded or in the schema directory.
+func (c *Context) SetAPIVersion(version string) error {
+	if !isAPIVersion(version) {
+		return NewFlagError(UsageAPIVersion)
+	}
+	if _, err := db.OpenConfig(c.databaseConfig(version, false)); err != nil {
+		return err
+	}
+	*c.opts.APIVersion = version
+
+	return nil
+}
+
+// SetCache enables or disables the cache.
+func (c *Context) SetCache(enable bool) {
+	*c.opts.Caching = enable
+}
+
+// IncludeReportSummary returns true if the totals computed by Adwords are displayed after the records.
+func (c *Context) IncludeReportSummary() bool {
+	return *c.opts.Summary
+}
+
 // IsInteractive returns true if query as passed as flag.
 func (c *Context) IsInteractive() bool {
 	return *c.opts.Query == ""
@@ -154,6 +414,11 @@ func (c *Context) UseBatchMode() bool {
 	return *c.opts.Batch
 }
 
+// UseRawEnumValues returns true if the enum values are returned as defined in the API.
+func (c *Context) UseRawEnumValues() bool {
+	return *c.opts.RawEnumValues
+}
+
 // UseVerboseMode returns true if more output about what the program does is required.
 func (c *Context) UseVerboseMode() bool {
 	return *c.opts.Verbose
@@ -177,6 +442,89 @@ func (c *Context) filePath() string {
 	return filepath.Join(c.homeDir, "config")
 }
 
+// aliases returns the ID of the accounts by alias, as defined in the accounts file.
+// Without accounts file, there is no alias.
+func (c *Context) aliases() (map[string]string, error) {
+	buf, err := ioutil.ReadFile(c.AccountsFile())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var aliases map[string]string
+	if err := yaml.Unmarshal(buf, &aliases); err != nil {
+		return nil, NewFlagError(UsageAccounts)
+	}
+	for _, id := range aliases {
+		if !isAccountID(id) {
+			return nil, NewFlagError(UsageAccounts)
+		}
+	}
+	return aliases, nil
+}
+
+// importHistory copies the legacy history file, shared by all the accounts, as the history file of this account.
+// The legacy file is kept for the other accounts. Nothing is done if the history file of the account already exists.
+func (c *Context) importHistory(id string) error {
+	if c.homeDir == "" {
+		return nil
+	}
+	if _, err := os.Stat(c.historyFile(id)); !os.IsNotExist(err) {
+		return nil
+	}
+	legacy := filepath.Join(c.homeDir, "history")
+	fi, err := os.Stat(legacy)
+	if err != nil {
+		// No legacy history.
+		return nil
+	}
+	buf, err := ioutil.ReadFile(legacy)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.historyFile(id), buf, fi.Mode().Perm())
+}
+
+// importViews copies the legacy views file of the database directory in the home directory.
+// Nothing is done if the views file of the user already exists.
+func (c *Context) importViews() error {
+	if _, err := os.Stat(c.ViewsFile()); !os.IsNotExist(err) {
+		return nil
+	}
+	buf, err := ioutil.ReadFile(filepath.Join(c.DatabaseDir(), "views.yml"))
+	if err != nil {
+		// No legacy views.
+		return nil
+	}
+	return ioutil.WriteFile(c.ViewsFile(), buf, 0644)
+}
+
+// openDatabase opens the database of the API version, with the views and the schema directory of the home directory.
+// Without loading, only the version is checked.
+func (c *Context) openDatabase(load bool) (*db.Database, error) {
+	if err := c.mkDirHome(); err != nil {
+		return nil, err
+	}
+	if err := c.importViews(); err != nil {
+		return nil, err
+	}
+	return db.OpenConfig(c.databaseConfig(c.APIVersion(), load))
+}
+
+// databaseConfig returns the configuration of the database of this API version,
+// with the views and the schema directory of the home directory.
+// Without loading, only the version is checked.
+func (c *Context) databaseConfig(version string, load bool) db.Config {
+	return db.Config{
+		Version:     version,
+		ViewsFile:   c.ViewsFile(),
+		CatalogFile: c.CatalogFile(),
+		SchemaDir:   c.SchemaDir(),
+		NoLoad:      !load,
+	}
+}
+
 // mkDirHome creates if not already exists the home directory.
 func (c *Context) mkDirHome() error {
 	if c.homeDir != "" {
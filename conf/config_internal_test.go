@@ -0,0 +1,170 @@
+package conf
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newContext returns a context using the account and a temporary home directory with this accounts file.
+// Without accounts file, the content is empty.
+func newContext(t *testing.T, id, accounts string) (*Context, func()) {
+	dir, err := ioutil.TempDir("", "awql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accounts != "" {
+		if err := ioutil.WriteFile(filepath.Join(dir, "accounts.yml"), []byte(accounts), 0644); err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+	}
+	c := &Context{homeDir: dir, opts: &Flag{AccountID: &id}}
+	return c, func() { os.RemoveAll(dir) }
+}
+
+// TestContext_aliases tests the method named aliases on Context struct.
+func TestContext_aliases(t *testing.T) {
+	var aliasTests = []struct {
+		accounts string
+		aliases  map[string]string
+		err      bool
+	}{
+		{},
+		{accounts: "acme-fr: 123-456-7890\nacme-de: 234-567-8901\n", aliases: map[string]string{
+			"acme-fr": "123-456-7890", "acme-de": "234-567-8901",
+		}},
+		{accounts: "acme-fr: 1234567890\n", err: true},
+		{accounts: "- 123-456-7890\n", err: true},
+	}
+	for i, tt := range aliasTests {
+		c, done := newContext(t, "123-456-7890", tt.accounts)
+		aliases, err := c.aliases()
+		done()
+		if tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if !tt.err && !reflect.DeepEqual(aliases, tt.aliases) {
+			t.Errorf("%d. Expected %v, received %v", i, tt.aliases, aliases)
+		}
+	}
+}
+
+// TestContext_Accounts tests the method named Accounts on Context struct.
+func TestContext_Accounts(t *testing.T) {
+	const accounts = "acme-fr: 123-456-7890\nacme-de: 234-567-8901\nacme: 123-456-7890\n"
+	var accountTests = []struct {
+		id, accounts string
+		list         []Account
+		err          bool
+	}{
+		{id: "123-456-7890", list: []Account{{ID: "123-456-7890"}}},
+		{id: "123-456-7890", accounts: "acme-fr: oops\n", err: true},
+		{
+			// The current account has aliases.
+			id:       "123-456-7890",
+			accounts: accounts,
+			list: []Account{
+				{ID: "123-456-7890", Alias: "acme"},
+				{ID: "234-567-8901", Alias: "acme-de"},
+				{ID: "123-456-7890", Alias: "acme-fr"},
+			},
+		},
+		{
+			// The current account has no alias, it is listed after the other ones.
+			id:       "345-678-9012",
+			accounts: accounts,
+			list: []Account{
+				{ID: "123-456-7890", Alias: "acme"},
+				{ID: "234-567-8901", Alias: "acme-de"},
+				{ID: "123-456-7890", Alias: "acme-fr"},
+				{ID: "345-678-9012"},
+			},
+		},
+	}
+	for i, tt := range accountTests {
+		c, done := newContext(t, tt.id, tt.accounts)
+		list, err := c.Accounts()
+		done()
+		if tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if !tt.err && !reflect.DeepEqual(list, tt.list) {
+			t.Errorf("%d. Expected %v, received %v", i, tt.list, list)
+		}
+	}
+}
+
+// TestContext_SetAccount tests the method named SetAccount on Context struct.
+func TestContext_SetAccount(t *testing.T) {
+	const accounts = "acme-fr: 123-456-7890\nacme-de: 234-567-8901\n"
+	var accountTests = []struct {
+		name, accounts, id string
+		err                bool
+	}{
+		{name: "234-567-8901", id: "234-567-8901"},
+		{name: "345-678-9012", accounts: accounts, id: "345-678-9012"},
+		{name: "acme-de", accounts: accounts, id: "234-567-8901"},
+		{name: "acme-de", err: true},
+		{name: "acme-it", accounts: accounts, err: true},
+		{name: "acme-de", accounts: "acme-de: oops\n", err: true},
+	}
+	for i, tt := range accountTests {
+		c, done := newContext(t, "123-456-7890", tt.accounts)
+		err := c.SetAccount(tt.name)
+		id := c.AccountID()
+		done()
+		if tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if tt.err && id != "123-456-7890" {
+			t.Errorf("%d. Expected the account unchanged, received %v", i, id)
+		} else if !tt.err && id != tt.id {
+			t.Errorf("%d. Expected %v, received %v", i, tt.id, id)
+		}
+	}
+}
+
+// TestContext_importHistory tests that the legacy history is copied for each account and kept.
+func TestContext_importHistory(t *testing.T) {
+	c, done := newContext(t, "123-456-7890", "")
+	defer done()
+
+	// Without legacy history, nothing is done.
+	if err := c.importHistory(c.AccountID()); err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	if _, err := os.Stat(c.HistoryFile()); !os.IsNotExist(err) {
+		t.Errorf("Expected no history, received %v", err)
+	}
+	legacy := filepath.Join(c.homeDir, "history")
+	if err := ioutil.WriteFile(legacy, []byte("SHOW TABLES;\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(c.historyFile("234-567-8901"), []byte("DESC CAMPAIGN_PERFORMANCE_REPORT;\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.importHistory(c.AccountID()); err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	// The account without history gets a copy of the legacy one on its switch, the others keep theirs.
+	for _, id := range []string{"345-678-9012", "234-567-8901"} {
+		if err := c.SetAccount(id); err != nil {
+			t.Fatalf("Expected no error with %s, received %s", id, err)
+		}
+	}
+	var historyTests = []struct {
+		file, data string
+	}{
+		{file: legacy, data: "SHOW TABLES;\n"},
+		{file: c.historyFile("123-456-7890"), data: "SHOW TABLES;\n"},
+		{file: c.historyFile("345-678-9012"), data: "SHOW TABLES;\n"},
+		{file: c.historyFile("234-567-8901"), data: "DESC CAMPAIGN_PERFORMANCE_REPORT;\n"},
+	}
+	for i, tt := range historyTests {
+		if buf, err := ioutil.ReadFile(tt.file); err != nil {
+			t.Errorf("%d. Expected no error, received %s", i, err)
+		} else if string(buf) != tt.data {
+			t.Errorf("%d. Expected %q, received %q", i, tt.data, buf)
+		}
+	}
+}
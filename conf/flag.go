@@ -3,6 +3,7 @@ package conf
 import (
 	"flag"
 	"fmt"
+	"os"
 	"regexp"
 
 	awql "github.com/rvflash/awql-driver"
@@ -15,6 +16,27 @@ const (
 	UsageDeveloperToken = "Google OAuth developer token"
 	UsageAPIVersion     = "Google Adwords API version"
 	UsageQuery          = "Execute AWQL statement"
+	UsageCatalog        = "Path to a read-only catalog of views, shared by a team"
+	UsageSchemaDir      = "Path to a directory of API schemas, with a reports.yml file by version as v201809/reports.yml"
+	UsageCheckSchema    = "Checks the structure of a reports.yml schema file, then exits"
+	UsageSchemaChanges  = "Lists the changes of the reports from this API version to the one given with -V, then exits"
+	UsageTable          = "Only lists the changes of this table with -schema-changes"
+	UsageCheckViews     = "Checks the views against the API version given with -V, then exits"
+	UsageRenameColumns  = "Path to a Yaml file of the columns to rename in the views, as OldName: NewName, before checking them"
+	UsageDocs           = "Generates in this directory the HTML and Markdown documentation of the reports and views of the API version, then exits"
+	UsageGoogleAds      = "Sends the queries to the Google Ads API, translated in GAQL, instead of the Adwords reports"
+	UsageAdsEndpoint    = "Base URL of the Google Ads API"
+	UsageAdsVersion     = "Google Ads API version"
+	UsageLoginCustomer  = "Google Ads manager account ID to use as login customer"
+	UsageAccounts       = "Yaml file of the Google Adwords accounts by alias, as acme-fr: 123-456-7890"
+)
+
+// Names of the environment variables used as default paths.
+const (
+	// CatalogEnv is the default path of the catalog of views.
+	CatalogEnv = "AWQL_CATALOG"
+	// SchemaEnv is the default path of the directory of API schemas.
+	SchemaEnv = "AWQL_SCHEMA"
 )
 
 // FlagError represents an error for the command-line tool.
@@ -36,10 +58,24 @@ func (e *FlagError) Error() string {
 type Flag struct {
 	AccountID,
 	AccessToken,
+	AdsEndpoint,
+	AdsVersion,
 	APIVersion,
+	Catalog,
+	CheckSchema,
 	DeveloperToken,
-	Query *string
+	Docs,
+	LoginCustomerID,
+	Query,
+	RenameColumns,
+	SchemaChanges,
+	SchemaDir,
+	Table *string
 	Batch,
+	CheckViews,
+	GoogleAds,
+	RawEnumValues,
+	Summary,
 	ZeroImpressions,
 	NoRehash,
 	Verbose,
@@ -47,14 +83,22 @@ type Flag struct {
 }
 
 // Check checks all required inputs.
+// The tools on the schema and the views only require the API version.
 func (o *Flag) Check() error {
+	// Adwords API version support.
+	if !isAPIVersion(*o.APIVersion) {
+		return NewFlagError(UsageAPIVersion)
+	}
+	if o.UseTool() {
+		return nil
+	}
 	// Expected account identifier like 123-456-7890
-	if ok, _ := regexp.MatchString("^[0-9]{3}-[0-9]{3}-[0-9]{4}$", *o.AccountID); !ok {
+	if !isAccountID(*o.AccountID) {
 		return NewFlagError(UsageAccountID)
 	}
-	// Adwords API version support.
-	if ok, _ := regexp.MatchString("^v[0-9]{6}$", *o.APIVersion); !ok {
-		return NewFlagError(UsageAPIVersion)
+	// Manager account identifier, as the account one.
+	if *o.LoginCustomerID != "" && !isAccountID(*o.LoginCustomerID) {
+		return NewFlagError(UsageLoginCustomer)
 	}
 	// Authenticate credentials.
 	if *o.AccessToken != "" && *o.DeveloperToken == "" {
@@ -66,6 +110,23 @@ func (o *Flag) Check() error {
 	return nil
 }
 
+// UseTool returns true if a tool on the schema or the views is used, without connecting to Adwords.
+func (o *Flag) UseTool() bool {
+	return *o.CheckSchema != "" || *o.SchemaChanges != "" || *o.CheckViews || *o.RenameColumns != "" || *o.Docs != ""
+}
+
+// isAccountID returns true if the string is formatted as an account ID, like 123-456-7890.
+func isAccountID(s string) bool {
+	ok, _ := regexp.MatchString("^[0-9]{3}-[0-9]{3}-[0-9]{4}$", s)
+	return ok
+}
+
+// isAPIVersion returns true if the string is formatted as an Adwords API version, like v201809.
+func isAPIVersion(s string) bool {
+	ok, _ := regexp.MatchString("^v[0-9]{6}$", s)
+	return ok
+}
+
 // Parse parses the command-line flags from os.Args[1:]
 // It returns an instance of Flag.
 func Parse() *Flag {
@@ -78,6 +139,26 @@ func Parse() *Flag {
 	opts.DeveloperToken = flag.String("D", "", UsageDeveloperToken)
 	// Google Adwords API version.
 	opts.APIVersion = flag.String("V", awql.APIVersion, UsageAPIVersion)
+	// Read-only catalog of views.
+	opts.Catalog = flag.String("C", os.Getenv(CatalogEnv), UsageCatalog+" (default $"+CatalogEnv+")")
+	// Directory of the schemas of the API versions not embedded in the tool.
+	opts.SchemaDir = flag.String("S", os.Getenv(SchemaEnv), UsageSchemaDir+" (default $"+SchemaEnv+" or ~/.awql/schema)")
+	// Schema file to check (non interactive use).
+	opts.CheckSchema = flag.String("check-schema", "", UsageCheckSchema)
+	// Version of the API to compare with the one in use, only for a table if given (non interactive use).
+	opts.SchemaChanges = flag.String("schema-changes", "", UsageSchemaChanges)
+	opts.Table = flag.String("table", "", UsageTable)
+	// Checks the views against the version of the API (non interactive use).
+	opts.CheckViews = flag.Bool("check-views", false, UsageCheckViews)
+	// Columns to rename in the views before checking them.
+	opts.RenameColumns = flag.String("rename-columns", "", UsageRenameColumns)
+	// Directory of the documentation of the schema (non interactive use).
+	opts.Docs = flag.String("docs", "", UsageDocs)
+	// Google Ads API as backend, with its endpoint, its version and the manager account to use.
+	opts.GoogleAds = flag.Bool("ads", false, UsageGoogleAds)
+	opts.AdsEndpoint = flag.String("ads-endpoint", "", UsageAdsEndpoint)
+	opts.AdsVersion = flag.String("ads-version", awql.AdsAPIVersion, UsageAdsVersion)
+	opts.LoginCustomerID = flag.String("login-customer-id", "", UsageLoginCustomer)
 	// Awql query (non interactive use).
 	opts.Query = flag.String("e", "", UsageQuery+", disables interactive use")
 	// Disables automatic rehashing.
@@ -86,6 +167,10 @@ func Parse() *Flag {
 	opts.Batch = flag.Bool("B", false, "Enables printing of results using comma as the column separator")
 	// Supports zero impressions
 	opts.ZeroImpressions = flag.Bool("z", false, "Enables fetching of reports with the support of zero impressions")
+	// Enum values as defined in the API.
+	opts.RawEnumValues = flag.Bool("raw-enums", false, "Enables the enum values as defined in the API, as ENABLED instead of enabled")
+	// Report summary.
+	opts.Summary = flag.Bool("summary", false, "Enables the display of the totals computed by Adwords after the records")
 	// Verbose mode.
 	opts.Verbose = flag.Bool("v", false, "Enables verbose mode")
 	// Data caching.
@@ -0,0 +1,110 @@
+package conf_test
+
+import (
+	"testing"
+
+	"github.com/rvflash/awql/conf"
+)
+
+// newFlag returns the flags with their default value, updated by the function.
+func newFlag(fn func(o *conf.Flag)) *conf.Flag {
+	var (
+		accountID, accessToken, developerToken, loginCustomerID string
+		checkSchema, schemaChanges, renameColumns, docs         string
+		checkViews                                              bool
+		apiVersion                                              = "v201809"
+	)
+	o := &conf.Flag{
+		AccountID:       &accountID,
+		AccessToken:     &accessToken,
+		APIVersion:      &apiVersion,
+		CheckSchema:     &checkSchema,
+		CheckViews:      &checkViews,
+		DeveloperToken:  &developerToken,
+		Docs:            &docs,
+		LoginCustomerID: &loginCustomerID,
+		RenameColumns:   &renameColumns,
+		SchemaChanges:   &schemaChanges,
+	}
+	if fn != nil {
+		fn(o)
+	}
+	return o
+}
+
+// TestFlag_Check tests the method named Check on Flag struct.
+func TestFlag_Check(t *testing.T) {
+	var checkTests = []struct {
+		o   *conf.Flag
+		err bool
+	}{
+		{o: newFlag(nil), err: true},
+		{o: newFlag(func(o *conf.Flag) { *o.AccountID = "123-456-7890" })},
+		{o: newFlag(func(o *conf.Flag) { *o.AccountID = "1234567890" }), err: true},
+		{
+			o: newFlag(func(o *conf.Flag) {
+				*o.AccountID = "123-456-7890"
+				*o.APIVersion = "201809"
+			}),
+			err: true,
+		},
+		{
+			o: newFlag(func(o *conf.Flag) {
+				*o.AccountID = "123-456-7890"
+				*o.AccessToken = "ya29.AcC3s57okeN"
+			}),
+			err: true,
+		},
+		{
+			o: newFlag(func(o *conf.Flag) {
+				*o.AccountID = "123-456-7890"
+				*o.AccessToken = "ya29.AcC3s57okeN"
+				*o.DeveloperToken = "dEve1op3er7okeN"
+			}),
+		},
+		{
+			o: newFlag(func(o *conf.Flag) {
+				*o.AccountID = "123-456-7890"
+				*o.LoginCustomerID = "oops"
+			}),
+			err: true,
+		},
+		// The tools only require the API version.
+		{o: newFlag(func(o *conf.Flag) { *o.Docs = "./docs" })},
+		{o: newFlag(func(o *conf.Flag) { *o.CheckViews = true })},
+		{o: newFlag(func(o *conf.Flag) { *o.RenameColumns = "renames.yml" })},
+		{o: newFlag(func(o *conf.Flag) { *o.SchemaChanges = "v201806" })},
+		{
+			o: newFlag(func(o *conf.Flag) {
+				*o.Docs = "./docs"
+				*o.APIVersion = "latest"
+			}),
+			err: true,
+		},
+	}
+	for i, tt := range checkTests {
+		if err := tt.o.Check(); tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		}
+	}
+}
+
+// TestFlag_UseTool tests the method named UseTool on Flag struct.
+func TestFlag_UseTool(t *testing.T) {
+	var toolTests = []struct {
+		o  *conf.Flag
+		ok bool
+	}{
+		{o: newFlag(nil)},
+		{o: newFlag(func(o *conf.Flag) { *o.CheckSchema = "reports.yml" }), ok: true},
+		{o: newFlag(func(o *conf.Flag) { *o.SchemaChanges = "v201806" }), ok: true},
+		{o: newFlag(func(o *conf.Flag) { *o.CheckViews = true }), ok: true},
+		{o: newFlag(func(o *conf.Flag) { *o.RenameColumns = "renames.yml" }), ok: true},
+		{o: newFlag(func(o *conf.Flag) { *o.Docs = "./docs" }), ok: true},
+	}
+	for i, tt := range toolTests {
+		if ok := tt.o.UseTool(); ok != tt.ok {
+			t.Errorf("%d. Expected %v, received %v", i, tt.ok, ok)
+		}
+	}
+}
@@ -0,0 +1,334 @@
+package docs
+
+import (
+	"encoding/json"
+	htmltpl "html/template"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	texttpl "text/template"
+
+	db "github.com/rvflash/awql-db"
+)
+
+// Names of the files of the documentation.
+const (
+	IndexFile    = "index.html"
+	ReadmeFile   = "README.md"
+	SearchFile   = "search.js"
+	htmlFileExt  = ".html"
+	mdFileExt    = ".md"
+	searchPrefix = "var searchIndex = "
+)
+
+// Table represents a report or a view, as documented.
+type Table struct {
+	Name, Origin, Aggregate, Query string
+	View                           bool
+	Columns                        []Column
+	Views                          []string
+}
+
+// Column represents a column of a table, as documented.
+type Column struct {
+	Name, Source, Kind  string
+	Segment, Zero       bool
+	Enum, Incompatibles []string
+	Method              string
+}
+
+// entry represents a column or a table in the search index.
+type entry struct {
+	Name  string `json:"n"`
+	Table string `json:"t,omitempty"`
+	Kind  string `json:"k,omitempty"`
+	URL   string `json:"u"`
+}
+
+// Tables returns the tables of the database to document, sorted by name.
+// The views list their columns by alias and their query to rebuild them,
+// each table lists the views built on it.
+func Tables(d *db.Database) ([]Table, error) {
+	tables, err := d.Tables()
+	if err != nil {
+		return nil, err
+	}
+	// usedBy lists the names of the views by data source.
+	usedBy := make(map[string][]string)
+	for _, t := range tables {
+		if t.IsView() {
+			src := t.SourceQuery().SourceName()
+			usedBy[src] = append(usedBy[src], t.SourceName())
+		}
+	}
+
+	docs := make([]Table, len(tables))
+	for i, t := range tables {
+		doc := Table{
+			Name:      t.SourceName(),
+			Origin:    t.Origin(),
+			Aggregate: t.AggregateFieldName(),
+			View:      t.IsView(),
+			Views:     usedBy[t.SourceName()],
+		}
+		sort.Strings(doc.Views)
+		if doc.View {
+			stmt, err := d.ViewStmt(doc.Name)
+			if err != nil {
+				return nil, err
+			}
+			doc.Query = stmt.String()
+		}
+		for _, c := range t.Columns() {
+			f := c.(db.Field)
+			col := Column{
+				Name:          f.Name(),
+				Kind:          f.Kind(),
+				Segment:       f.IsSegment(),
+				Zero:          f.SupportsZeroImpressions(),
+				Enum:          f.ValueList(),
+				Incompatibles: f.NotCompatibleColumns(),
+			}
+			col.Method, _ = f.UseFunction()
+			if doc.View && f.Alias() != "" {
+				col.Name, col.Source = f.Alias(), f.Name()
+			}
+			doc.Columns = append(doc.Columns, col)
+		}
+		docs[i] = doc
+	}
+	sort.Sort(byName(docs))
+
+	return docs, nil
+}
+
+// byName sorts the tables by name.
+type byName []Table
+
+func (t byName) Len() int           { return len(t) }
+func (t byName) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t byName) Less(i, j int) bool { return t[i].Name < t[j].Name }
+
+// Generate writes the documentation of the tables of the database in a sub-directory
+// of the directory named by the version of the API: an HTML page and a Markdown file by table,
+// with an index of all the tables and a search index of their columns.
+// The index of the directory lists all the versions already documented.
+func Generate(d *db.Database, dir string) error {
+	tables, err := Tables(d)
+	if err != nil {
+		return err
+	}
+	out := filepath.Join(dir, d.Version)
+	if err := os.MkdirAll(out, os.ModePerm); err != nil {
+		return err
+	}
+	// write creates the file and writes in it with the function.
+	var write = func(name string, fn func(w io.Writer) error) error {
+		f, err := os.Create(filepath.Join(out, name))
+		if err != nil {
+			return err
+		}
+		err = fn(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	}
+
+	data := struct {
+		Version string
+		Tables  []Table
+	}{d.Version, tables}
+	for _, t := range tables {
+		tb := struct {
+			Version string
+			Table
+		}{d.Version, t}
+		if err := write(t.Name+htmlFileExt, func(w io.Writer) error {
+			return htmlTable.Execute(w, tb)
+		}); err != nil {
+			return err
+		}
+		if err := write(t.Name+mdFileExt, func(w io.Writer) error {
+			return mdTable.Execute(w, tb)
+		}); err != nil {
+			return err
+		}
+	}
+	if err := write(IndexFile, func(w io.Writer) error {
+		return htmlIndex.Execute(w, data)
+	}); err != nil {
+		return err
+	}
+	if err := write(ReadmeFile, func(w io.Writer) error {
+		return mdIndex.Execute(w, data)
+	}); err != nil {
+		return err
+	}
+	if err := write(SearchFile, func(w io.Writer) error {
+		return writeSearchIndex(w, tables)
+	}); err != nil {
+		return err
+	}
+	return writeVersions(dir)
+}
+
+// writeSearchIndex writes the names of the tables and of their columns with their link,
+// as a JavaScript variable to be usable without web server.
+func writeSearchIndex(w io.Writer, tables []Table) error {
+	// Without table, the index is an empty array.
+	list := []entry{}
+	for _, t := range tables {
+		list = append(list, entry{Name: t.Name, URL: t.Name + htmlFileExt})
+		for _, c := range t.Columns {
+			list = append(list, entry{Name: c.Name, Table: t.Name, Kind: c.Kind, URL: t.Name + htmlFileExt + "#" + c.Name})
+		}
+	}
+	buf, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	if _, err = io.WriteString(w, searchPrefix); err != nil {
+		return err
+	}
+	if _, err = w.Write(buf); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, ";\n")
+	return err
+}
+
+// writeVersions writes the index of the directory with the versions of the API documented in it.
+func writeVersions(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var versions []string
+	for _, f := range files {
+		if !f.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, f.Name(), IndexFile)); err == nil {
+			versions = append(versions, f.Name())
+		}
+	}
+	// The most recent first.
+	sort.Sort(sort.Reverse(sort.StringSlice(versions)))
+
+	f, err := os.Create(filepath.Join(dir, IndexFile))
+	if err != nil {
+		return err
+	}
+	err = htmlVersions.Execute(f, versions)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Templates of the documentation.
+var (
+	htmlVersions = htmltpl.Must(htmltpl.New("versions").Parse(htmlHeader + `
+<h1>AWQL schema</h1>
+<ul>
+{{range .}}<li><a href="{{.}}/index.html">{{.}}</a></li>
+{{end}}</ul>
+` + htmlFooter))
+
+	htmlIndex = htmltpl.Must(htmltpl.New("index").Parse(htmlHeader + `
+<p><a href="../index.html">All versions</a></p>
+<h1>AWQL schema {{.Version}}</h1>
+<input id="q" type="search" placeholder="Search a table or a column" autofocus>
+<ul id="results"></ul>
+<h2>Tables</h2>
+<table>
+<tr><th>Name</th><th>Origin</th><th>Columns</th></tr>
+{{range .Tables}}<tr><td><a href="{{.Name}}.html">{{.Name}}</a></td><td>{{.Origin}}</td><td>{{len .Columns}}</td></tr>
+{{end}}</table>
+<script src="search.js"></script>
+<script>
+var q = document.getElementById("q"), results = document.getElementById("results");
+q.addEventListener("input", function () {
+  var s = q.value.toLowerCase(), n = 0;
+  results.innerHTML = "";
+  for (var i = 0; s.length > 1 && i < searchIndex.length && n < 100; i++) {
+    var e = searchIndex[i];
+    if (e.n.toLowerCase().indexOf(s) < 0) continue;
+    var li = document.createElement("li"), a = document.createElement("a");
+    a.href = e.u;
+    a.textContent = e.n;
+    li.appendChild(a);
+    if (e.t) li.appendChild(document.createTextNode(" (" + e.t + ", " + e.k + ")"));
+    results.appendChild(li);
+    n++;
+  }
+});
+</script>
+` + htmlFooter))
+
+	htmlTable = htmltpl.Must(htmltpl.New("table").Parse(htmlHeader + `
+<p><a href="index.html">AWQL schema {{.Version}}</a></p>
+<h1 id="{{.Name}}">{{.Name}}</h1>
+<p>Origin: {{.Origin}}{{if .Aggregate}}, aggregated by <a href="#{{.Aggregate}}">{{.Aggregate}}</a>{{end}}</p>
+{{if .View}}<pre>{{.Query}}</pre>
+{{end}}{{if .Views}}<p>Views: {{range $i, $v := .Views}}{{if $i}}, {{end}}<a href="{{$v}}.html">{{$v}}</a>{{end}}</p>
+{{end}}<table>
+<tr><th>Column</th><th>Kind</th><th>Segment</th><th>Zero impressions</th><th>Values</th><th>Not compatible with</th></tr>
+{{range .Columns}}<tr id="{{.Name}}">
+<td><a href="#{{.Name}}">{{.Name}}</a>{{if .Source}} ({{.Source}}){{end}}{{if .Method}} {{.Method}}{{end}}</td>
+<td>{{.Kind}}</td>
+<td>{{if .Segment}}Yes{{end}}</td>
+<td>{{if .Zero}}Yes{{end}}</td>
+<td>{{range $i, $v := .Enum}}{{if $i}}, {{end}}{{$v}}{{end}}</td>
+<td>{{range $i, $v := .Incompatibles}}{{if $i}}, {{end}}<a href="#{{$v}}">{{$v}}</a>{{end}}</td>
+</tr>
+{{end}}</table>
+` + htmlFooter))
+
+	mdIndex = texttpl.Must(texttpl.New("index").Parse(`# AWQL schema {{.Version}}
+
+| Name | Origin | Columns |
+|------|--------|---------|
+{{range .Tables}}| [{{.Name}}]({{.Name}}.md) | {{.Origin}} | {{len .Columns}} |
+{{end}}`))
+
+	mdTable = texttpl.Must(texttpl.New("table").Parse(`# {{.Name}}
+
+[AWQL schema {{.Version}}](README.md)
+
+Origin: {{.Origin}}{{if .Aggregate}}, aggregated by {{.Aggregate}}{{end}}
+{{if .View}}
+` + "```sql" + `
+{{.Query}}
+` + "```" + `
+{{end}}{{if .Views}}
+Views: {{range $i, $v := .Views}}{{if $i}}, {{end}}[{{$v}}]({{$v}}.md){{end}}
+{{end}}
+| Column | Kind | Segment | Zero impressions | Values | Not compatible with |
+|--------|------|---------|------------------|--------|---------------------|
+{{range .Columns}}| {{.Name}}{{if .Source}} ({{.Source}}){{end}}{{if .Method}} {{.Method}}{{end}} | {{.Kind}} | {{if .Segment}}Yes{{end}} | {{if .Zero}}Yes{{end}} | {{range $i, $v := .Enum}}{{if $i}}, {{end}}{{$v}}{{end}} | {{range $i, $v := .Incompatibles}}{{if $i}}, {{end}}{{$v}}{{end}} |
+{{end}}`))
+)
+
+const htmlHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>AWQL schema</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+tr:target { background: #ffc; }
+pre { background: #f4f4f4; padding: 1em; white-space: pre-wrap; }
+</style>
+</head>
+<body>`
+
+const htmlFooter = `
+</body>
+</html>
+`
@@ -0,0 +1,218 @@
+package docs
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	db "github.com/rvflash/awql-db"
+	parser "github.com/rvflash/awql-parser"
+)
+
+// Fixture of the schema, with one report.
+const (
+	version = "v201902"
+	schema  = `reports:
+  - name: CAMPAIGN_PERFORMANCE_REPORT
+    aggr: CampaignId
+    cols:
+      - name: CampaignId
+        kind: Long
+        zero: true
+      - name: CampaignStatus
+        kind: CampaignStatus
+        enum: [ ENABLED, PAUSED ]
+        zero: true
+      - name: Clicks
+        kind: Long
+        notc: [ Date ]
+      - name: Date
+        kind: Date
+        sgmt: true
+`
+)
+
+// openSchema returns the database of the fixture with a view on its report, in a temporary directory.
+func openSchema(t *testing.T) (*db.Database, string, func()) {
+	dir, err := ioutil.TempDir("", "awql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := func() { os.RemoveAll(dir) }
+	if err := os.MkdirAll(filepath.Join(dir, "schema", version), os.ModePerm); err != nil {
+		done()
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "schema", version, db.SchemaFile), []byte(schema), 0644); err != nil {
+		done()
+		t.Fatal(err)
+	}
+	d, err := db.OpenConfig(db.Config{
+		Version:   version,
+		ViewsFile: filepath.Join(dir, "views.yml"),
+		SchemaDir: filepath.Join(dir, "schema"),
+	})
+	if err != nil {
+		done()
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	q := "CREATE VIEW ENABLED_CAMPAIGN (Id, Clicks) AS SELECT CampaignId, Clicks FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignStatus = 'ENABLED'"
+	stmt, err := parser.NewParser(strings.NewReader(q)).ParseRow()
+	if err != nil {
+		done()
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	if err := d.AddView(stmt.(parser.CreateViewStmt)); err != nil {
+		done()
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	return d, dir, done
+}
+
+// TestTables tests the function named Tables.
+func TestTables(t *testing.T) {
+	d, _, done := openSchema(t)
+	defer done()
+
+	out := []Table{
+		{
+			Name:      "CAMPAIGN_PERFORMANCE_REPORT",
+			Origin:    db.OriginAdwords,
+			Aggregate: "CampaignId",
+			Columns: []Column{
+				{Name: "CampaignId", Kind: "Long", Zero: true},
+				{Name: "CampaignStatus", Kind: "CampaignStatus", Zero: true, Enum: []string{"ENABLED", "PAUSED"}},
+				{Name: "Clicks", Kind: "Long", Incompatibles: []string{"Date"}},
+				{Name: "Date", Kind: "Date", Segment: true},
+			},
+			Views: []string{"ENABLED_CAMPAIGN"},
+		},
+		{
+			Name:      "ENABLED_CAMPAIGN",
+			Origin:    db.OriginUser,
+			Aggregate: "CampaignId",
+			Query:     `CREATE VIEW ENABLED_CAMPAIGN (Id, Clicks) AS SELECT CampaignId, Clicks FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignStatus = "ENABLED"`,
+			View:      true,
+			Columns: []Column{
+				{Name: "Id", Source: "CampaignId", Kind: "Long", Zero: true},
+				{Name: "Clicks", Source: "Clicks", Kind: "Long", Incompatibles: []string{"Date"}},
+			},
+		},
+	}
+	tables, err := Tables(d)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	if !reflect.DeepEqual(tables, out) {
+		t.Errorf("Expected %+v, received %+v", out, tables)
+	}
+}
+
+// TestGenerate tests the function named Generate.
+func TestGenerate(t *testing.T) {
+	d, dir, done := openSchema(t)
+	defer done()
+
+	// A version already documented is listed in the index of the directory.
+	out := filepath.Join(dir, "docs")
+	if err := os.MkdirAll(filepath.Join(out, "v201809"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(out, "v201809", IndexFile), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Generate(d, out); err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	var fileTests = []struct {
+		name     string
+		contains []string
+	}{
+		{
+			name: IndexFile,
+			contains: []string{
+				`<li><a href="v201902/index.html">v201902</a></li>
+<li><a href="v201809/index.html">v201809</a></li>`,
+			},
+		},
+		{
+			name: filepath.Join(version, IndexFile),
+			contains: []string{
+				"<h1>AWQL schema v201902</h1>",
+				`<tr><td><a href="CAMPAIGN_PERFORMANCE_REPORT.html">CAMPAIGN_PERFORMANCE_REPORT</a></td><td>ADWORDS</td><td>4</td></tr>`,
+				`<tr><td><a href="ENABLED_CAMPAIGN.html">ENABLED_CAMPAIGN</a></td><td>USER</td><td>2</td></tr>`,
+			},
+		},
+		{
+			name: filepath.Join(version, ReadmeFile),
+			contains: []string{`# AWQL schema v201902
+
+| Name | Origin | Columns |
+|------|--------|---------|
+| [CAMPAIGN_PERFORMANCE_REPORT](CAMPAIGN_PERFORMANCE_REPORT.md) | ADWORDS | 4 |
+| [ENABLED_CAMPAIGN](ENABLED_CAMPAIGN.md) | USER | 2 |
+`},
+		},
+		{
+			name: filepath.Join(version, "CAMPAIGN_PERFORMANCE_REPORT.html"),
+			contains: []string{
+				`<p>Origin: ADWORDS, aggregated by <a href="#CampaignId">CampaignId</a></p>`,
+				`<p>Views: <a href="ENABLED_CAMPAIGN.html">ENABLED_CAMPAIGN</a></p>`,
+				"<td>ENABLED, PAUSED</td>",
+			},
+		},
+		{
+			name: filepath.Join(version, "ENABLED_CAMPAIGN.md"),
+			contains: []string{
+				"CREATE VIEW ENABLED_CAMPAIGN (Id, Clicks) AS SELECT CampaignId, Clicks FROM CAMPAIGN_PERFORMANCE_REPORT",
+				"| Id (CampaignId) | Long |  | Yes |  |  |",
+				"| Clicks (Clicks) | Long |  |  |  | Date |",
+			},
+		},
+		{
+			name:     filepath.Join(version, SearchFile),
+			contains: []string{searchPrefix, `{"n":"Id","t":"ENABLED_CAMPAIGN","k":"Long","u":"ENABLED_CAMPAIGN.html#Id"}`},
+		},
+	}
+	for i, tt := range fileTests {
+		buf, err := ioutil.ReadFile(filepath.Join(out, tt.name))
+		if err != nil {
+			t.Errorf("%d. Expected no error, received %s", i, err)
+			continue
+		}
+		for _, s := range tt.contains {
+			if !strings.Contains(string(buf), s) {
+				t.Errorf("%d. Expected %q in %s, received %q", i, s, tt.name, buf)
+			}
+		}
+	}
+}
+
+// TestWriteSearchIndex tests the function named writeSearchIndex.
+func TestWriteSearchIndex(t *testing.T) {
+	var searchTests = []struct {
+		tables []Table
+		out    string
+	}{
+		{out: "var searchIndex = [];\n"},
+		{
+			tables: []Table{
+				{Name: "A", Columns: []Column{{Name: "B", Kind: "Long"}}},
+				{Name: "C"},
+			},
+			out: `var searchIndex = [{"n":"A","u":"A.html"},{"n":"B","t":"A","k":"Long","u":"A.html#B"},{"n":"C","u":"C.html"}];` + "\n",
+		},
+	}
+	for i, tt := range searchTests {
+		var buf bytes.Buffer
+		if err := writeSearchIndex(&buf, tt.tables); err != nil {
+			t.Errorf("%d. Expected no error, received %s", i, err)
+		} else if out := buf.String(); out != tt.out {
+			t.Errorf("%d. Expected %q, received %q", i, tt.out, out)
+		}
+	}
+}
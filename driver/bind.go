@@ -0,0 +1,207 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	parser "github.com/rvflash/awql-parser"
+)
+
+// token represents a lexical token of the query with its source text.
+type token struct {
+	tk       parser.Token
+	lit, raw string
+}
+
+// tokenize returns all the tokens of the query.
+func tokenize(q string) (tokens []token) {
+	s := parser.NewScanner(strings.NewReader(q))
+	for {
+		tk, lit := s.Scan()
+		if tk == parser.EOF {
+			return
+		}
+		tokens = append(tokens, token{tk: tk, lit: lit, raw: s.Raw()})
+	}
+}
+
+// numInput returns the number of placeholders in the query.
+// With named placeholders or mixed styles, it returns -1, the number of args can not be checked.
+// Placeholders inside quoted strings are ignored.
+func numInput(q string) int {
+	var n, max int
+	for _, t := range tokenize(q) {
+		if t.tk != parser.PLACEHOLDER {
+			continue
+		}
+		switch t.lit[0] {
+		case '?':
+			n++
+		case '$':
+			if max == 0 && n > 0 {
+				return -1
+			}
+			i, _ := strconv.Atoi(t.lit[1:])
+			if i > max {
+				max = i
+			}
+		default:
+			return -1
+		}
+	}
+	if max > 0 {
+		if n > 0 {
+			return -1
+		}
+		return max
+	}
+	return n
+}
+
+// bind replaces each placeholder of the query by the value of its argument.
+// Supported placeholders are "?", "$1" and ":name". Placeholders inside quoted strings are ignored.
+// A slice is expanded as list and the parentheses around it are replaced by square brackets,
+// as expected by the IN and NOT_IN operators.
+func bind(q string, args []driver.NamedValue) (string, error) {
+	// Retrieves the argument of the placeholder.
+	var pos int
+	var value = func(s string) (interface{}, error) {
+		switch s[0] {
+		case '?':
+			if pos >= len(args) {
+				return nil, NewXError("invalid binding", s)
+			}
+			pos++
+			return args[pos-1].Value, nil
+		case '$':
+			i, _ := strconv.Atoi(s[1:])
+			for _, a := range args {
+				if a.Ordinal == i {
+					return a.Value, nil
+				}
+			}
+		case ':':
+			for _, a := range args {
+				if a.Name == s[1:] {
+					return a.Value, nil
+				}
+			}
+		}
+		return nil, NewXError("invalid binding", s)
+	}
+	// Returns the index of the significant token around the position, -1 if there is none.
+	tokens := tokenize(q)
+	var around = func(at, step int) int {
+		for i := at + step; i >= 0 && i < len(tokens); i += step {
+			if tokens[i].tk != parser.WHITE_SPACE {
+				return i
+			}
+		}
+		return -1
+	}
+	for i, t := range tokens {
+		if t.tk != parser.PLACEHOLDER {
+			continue
+		}
+		v, err := value(t.lit)
+		if err != nil {
+			return "", err
+		}
+		s, list, err := formatArg(v)
+		if err != nil {
+			return "", NewXError("invalid binding", t.lit)
+		}
+		p, n := around(i, -1), around(i, 1)
+		switch {
+		case p > -1 && n > -1 &&
+			tokens[p].tk == parser.LEFT_PARENTHESIS && tokens[n].tk == parser.RIGHT_PARENTHESIS:
+			if pp := around(p, -1); pp > -1 && (tokens[pp].tk == parser.IN || tokens[pp].tk == parser.NOT_IN) {
+				tokens[p].raw, tokens[n].raw = "[", "]"
+			} else if list {
+				s = "[" + s + "]"
+			}
+		case list && (p < 0 || tokens[p].tk != parser.LEFT_SQUARE_BRACKETS):
+			s = "[" + s + "]"
+		}
+		tokens[i].raw = s
+	}
+	var buf []string
+	for _, t := range tokens {
+		buf = append(buf, t.raw)
+	}
+	return strings.Join(buf, ""), nil
+}
+
+// namedValues returns the values as positional arguments.
+func namedValues(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return nv
+}
+
+// formatArg returns the value as AWQL literal.
+// Strings are double-quoted, times are formatted as dates expected by the DURING clause.
+// With a slice, its values are joined with a comma and the boolean is true.
+func formatArg(v interface{}) (string, bool, error) {
+	if !isList(v) {
+		s, err := formatValue(v)
+		return s, false, err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Len() == 0 {
+		return "", true, ErrBinding
+	}
+	list := make([]string, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		dv, err := driver.DefaultParameterConverter.ConvertValue(rv.Index(i).Interface())
+		if err != nil {
+			return "", true, ErrBinding
+		}
+		if list[i], err = formatValue(dv); err != nil {
+			return "", true, err
+		}
+	}
+	return strings.Join(list, ","), true, nil
+}
+
+// formatValue returns the driver value as AWQL literal.
+func formatValue(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case int64:
+		return strconv.FormatInt(t, 10), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(t)), nil
+	case time.Time:
+		// As the dates expected by the DURING clause.
+		return t.Format(dateFormat), nil
+	case []byte:
+		return quote(string(t)), nil
+	case string:
+		return quote(t), nil
+	}
+	return "", ErrBinding
+}
+
+// isList returns true if the value is a list of values, except the slice of bytes.
+func isList(v interface{}) bool {
+	if _, ok := v.([]byte); ok {
+		return false
+	}
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
+
+// quote returns the string double-quoted, with its backslashes and double quotes escaped.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,143 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	awql "github.com/rvflash/awql-driver"
+)
+
+// TestNumInput tests the function named numInput.
+func TestNumInput(t *testing.T) {
+	var inputTests = []struct {
+		q string
+		n int
+	}{
+		{q: "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT", n: 0},
+		{q: "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignName = '?'", n: 0},
+		{q: "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignId = ? AND Clicks > ?", n: 2},
+		{q: "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignId = $2 AND Clicks > $1", n: 2},
+		{q: "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignId = :id", n: -1},
+		{q: "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignId = ? AND Clicks > $1", n: -1},
+		{q: "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignId = $1 AND Clicks > ?", n: -1},
+	}
+	for i, tt := range inputTests {
+		if n := numInput(tt.q); n != tt.n {
+			t.Errorf("%d. Expected %v, received %v", i, tt.n, n)
+		}
+	}
+}
+
+// TestBind tests the function named bind.
+func TestBind(t *testing.T) {
+	var (
+		day   = time.Date(2018, 1, 31, 0, 0, 0, 0, time.UTC)
+		query = "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT WHERE "
+	)
+	var bindTests = []struct {
+		q    string
+		args []driver.NamedValue
+		out  string
+		err  bool
+	}{
+		// Without placeholder.
+		{q: query + "Clicks > 10", out: query + "Clicks > 10"},
+		// Question marks.
+		{
+			q:    query + "CampaignId = ? AND Clicks > ?",
+			args: namedValues([]driver.Value{int64(123), 1.5}),
+			out:  query + "CampaignId = 123 AND Clicks > 1.5",
+		},
+		{q: query + "CampaignId = ?", err: true},
+		{q: query + "CampaignId = ?", args: namedValues([]driver.Value{struct{}{}}), err: true},
+		// Placeholders inside quoted strings are ignored.
+		{
+			q:    query + `CampaignName = '?' AND AdNetworkType1 = "$1" AND Url = ':url' AND Clicks > ?`,
+			args: namedValues([]driver.Value{int64(10)}),
+			out:  query + `CampaignName = '?' AND AdNetworkType1 = "$1" AND Url = ':url' AND Clicks > 10`,
+		},
+		// Ordinals.
+		{
+			q:    query + "Clicks > $2 AND Impressions > $1 AND Conversions > $2",
+			args: namedValues([]driver.Value{int64(1), int64(2)}),
+			out:  query + "Clicks > 2 AND Impressions > 1 AND Conversions > 2",
+		},
+		{
+			q:    query + "Cost > $1.5",
+			args: namedValues([]driver.Value{int64(1)}),
+			out:  query + "Cost > 1.5",
+		},
+		{q: query + "Clicks > $2", args: namedValues([]driver.Value{int64(1)}), err: true},
+		// Names.
+		{
+			q: query + "CampaignId = :id AND CampaignName = :name",
+			args: []driver.NamedValue{
+				{Name: "name", Ordinal: 1, Value: "Campaign #1"},
+				{Name: "id", Ordinal: 2, Value: int64(123)},
+			},
+			out: query + `CampaignId = 123 AND CampaignName = "Campaign #1"`,
+		},
+		{q: query + "CampaignId = :id", args: namedValues([]driver.Value{int64(123)}), err: true},
+		// Escaping.
+		{
+			q:    query + "CampaignName = ?",
+			args: namedValues([]driver.Value{`My "tiny" \ campaign`}),
+			out:  query + `CampaignName = "My \"tiny\" \\ campaign"`,
+		},
+		{
+			q:    query + "CampaignName = ? AND CampaignStatus = ?",
+			args: namedValues([]driver.Value{[]byte("Campaign #1"), true}),
+			out:  query + `CampaignName = "Campaign #1" AND CampaignStatus = TRUE`,
+		},
+		// Times.
+		{
+			q:    "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT DURING ?,?",
+			args: namedValues([]driver.Value{day, day.AddDate(0, 1, 0)}),
+			out:  "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT DURING 20180131,20180303",
+		},
+		// Lists.
+		{
+			q:    query + "CampaignId IN (?)",
+			args: namedValues([]driver.Value{[]int64{1, 2, 3}}),
+			out:  query + "CampaignId IN [1,2,3]",
+		},
+		{
+			q:    query + "CampaignId NOT_IN ( :ids )",
+			args: []driver.NamedValue{{Name: "ids", Ordinal: 1, Value: []int{1, 2}}},
+			out:  query + "CampaignId NOT_IN [ 1,2 ]",
+		},
+		{
+			q:    query + "CampaignName IN [?] AND CampaignId IN ?",
+			args: namedValues([]driver.Value{[]string{"a", `b"c`}, []int64{1}}),
+			out:  query + `CampaignName IN ["a","b\"c"] AND CampaignId IN [1]`,
+		},
+		{q: query + "CampaignId IN (?)", args: namedValues([]driver.Value{[]int64{}}), err: true},
+		{q: query + "CampaignId IN (?)", args: namedValues([]driver.Value{[]interface{}{struct{}{}}}), err: true},
+	}
+	for i, tt := range bindTests {
+		out, err := bind(tt.q, tt.args)
+		if tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if out != tt.out {
+			t.Errorf("%d. Expected %q, received %q", i, tt.out, out)
+		}
+	}
+}
+
+// TestStmt_BindNamed tests that the statement can be bound again with other arguments.
+func TestStmt_BindNamed(t *testing.T) {
+	q := "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignId = ?"
+	s := &Stmt{si: &awql.Stmt{SrcQuery: q}, query: q}
+	for _, id := range []int64{1, 2} {
+		if err := s.BindNamed(namedValues([]driver.Value{id})); err != nil {
+			t.Fatalf("Expected no error with %d, received %s", id, err)
+		}
+		if n := s.NumInput(); n != 1 {
+			t.Errorf("Expected 1 placeholder with %d, received %d", id, n)
+		}
+	}
+	if out := q[:len(q)-1] + "2"; s.si.SrcQuery != out {
+		t.Errorf("Expected %q, received %q", out, s.si.SrcQuery)
+	}
+}
@@ -0,0 +1,101 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	parser "github.com/rvflash/awql-parser"
+)
+
+// ShowSchemaChangesStmt represents a Show Schema Changes statement.
+type ShowSchemaChangesStmt struct {
+	*Stmt
+}
+
+// NewShowSchemaChangesStmt returns an instance of ShowSchemaChangesStmt.
+// It implements Queryer interface.
+func NewShowSchemaChangesStmt(stmt *Stmt) Queryer {
+	return &ShowSchemaChangesStmt{stmt}
+}
+
+// Query executes a Show Schema Changes query.
+// It returns the changes of the reports between two versions of the API, one by row,
+// as the tables or the columns added and removed, or the changes of kind, enum values,
+// segment flag and incompatible columns.
+func (s *ShowSchemaChangesStmt) Query() (driver.Rows, error) {
+	// Casts statement.
+	stmt := s.p.(parser.ShowSchemaChangesStmt)
+
+	// Loads the reports of both versions.
+	v1, v2 := stmt.Versions()
+	from, err := s.db.Schema(v1)
+	if err != nil {
+		return nil, fmt.Errorf("%s (%v)", err, v1)
+	}
+	to, err := s.db.Schema(v2)
+	if err != nil {
+		return nil, fmt.Errorf("%s (%v)", err, v2)
+	}
+	changes, err := from.SchemaChanges(to, stmt.SourceName())
+	if err != nil {
+		return nil, fmt.Errorf("%s (%v)", err, stmt.SourceName())
+	}
+	size := len(changes)
+	if size == 0 {
+		return &Rows{}, nil
+	}
+	rs := make([][]driver.Value, size)
+	for i, c := range changes {
+		rs[i] = []driver.Value{c.Table, c.Column, c.Kind, c.Detail}
+	}
+	return &Rows{
+		cols:  []string{"Table", "Column", "Change", "Detail"},
+		data:  rs,
+		size:  size,
+		typed: s.typed,
+	}, nil
+}
+
+// CheckViewsStmt represents a Check Views statement.
+type CheckViewsStmt struct {
+	*Stmt
+}
+
+// NewCheckViewsStmt returns an instance of CheckViewsStmt.
+// It implements Queryer interface.
+func NewCheckViewsStmt(stmt *Stmt) Queryer {
+	return &CheckViewsStmt{stmt}
+}
+
+// Query executes a Check Views query.
+// It returns the problems of the views with a version of the API, one by row,
+// as the unknown data sources or columns, the invalid expressions, the values
+// not in the enum list of a condition and the incompatible columns.
+// Without version, the views are checked against the current one.
+func (s *CheckViewsStmt) Query() (driver.Rows, error) {
+	// Casts statement.
+	stmt := s.p.(parser.CheckViewsStmt)
+
+	v := stmt.Version()
+	if v == "" {
+		v = s.db.Version
+	}
+	issues, err := s.db.CheckViews(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s (%v)", err, v)
+	}
+	size := len(issues)
+	if size == 0 {
+		return &Rows{}, nil
+	}
+	rs := make([][]driver.Value, size)
+	for i, c := range issues {
+		rs[i] = []driver.Value{c.View, c.Column, c.Kind, c.Detail}
+	}
+	return &Rows{
+		cols:  []string{"View", "Column", "Problem", "Detail"},
+		data:  rs,
+		size:  size,
+		typed: s.typed,
+	}, nil
+}
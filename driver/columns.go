@@ -0,0 +1,130 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	db "github.com/rvflash/awql-db"
+	parser "github.com/rvflash/awql-parser"
+)
+
+// ShowColumnsStmt represents a Show Columns statement.
+type ShowColumnsStmt struct {
+	*Stmt
+}
+
+// NewShowColumnsStmt returns an instance of ShowColumnsStmt.
+// It implements Queryer interface.
+func NewShowColumnsStmt(stmt *Stmt) Queryer {
+	return &ShowColumnsStmt{stmt}
+}
+
+// Query executes a Show Columns query.
+// It returns the properties of the columns of a table, as a Describe query,
+// filtered by the like or the where clause. With the compatible mode, it only lists the columns
+// which can be added to the selected ones without breaking their compatibility.
+func (s *ShowColumnsStmt) Query() (driver.Rows, error) {
+	// Casts statement.
+	stmt := s.p.(parser.ShowColumnsStmt)
+
+	// Checks it the table exists.
+	tb, err := s.table(stmt.SourceName())
+	if err != nil {
+		return nil, err
+	}
+
+	// columnName returns the name of the column, or its alias in a view.
+	var columnName = func(f db.Field) string {
+		if tb.IsView() && f.Alias() != "" {
+			return f.Alias()
+		}
+		return f.Name()
+	}
+	fields := tb.Columns()
+	if cols, ok := stmt.CompatibleWith(); ok {
+		if fields, err = compatibleFields(tb, cols); err != nil {
+			return nil, err
+		}
+	}
+	if p, ok := stmt.LikePattern(); ok {
+		var list []parser.DynamicField
+		for _, c := range fields {
+			if p.Match(columnName(c.(db.Field))) {
+				list = append(list, c)
+			}
+		}
+		fields = list
+	}
+	rs := s.describe(tb, fields, stmt.FullMode())
+	if where := stmt.ConditionList(); len(where) > 0 {
+		if err := rs.filter(where); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
+
+// compatibleFields returns the columns of the table which are compatible with all the selected ones.
+// The selected columns are excluded from the list.
+func compatibleFields(tb db.DataTable, columns []string) ([]parser.DynamicField, error) {
+	selected := make([]db.Field, len(columns))
+	for i, name := range columns {
+		f, err := tb.Field(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s (%v)", db.ErrUnknownColumn, name)
+		}
+		selected[i] = f
+	}
+
+	var fields []parser.DynamicField
+	for _, c := range tb.Columns() {
+		f := c.(db.Field)
+		ok := true
+		for _, u := range selected {
+			if f.Name() == u.Name() || containsValue(f.NotCompatibleColumns(), u.Name()) || containsValue(u.NotCompatibleColumns(), f.Name()) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			fields = append(fields, c)
+		}
+	}
+	return fields, nil
+}
+
+// filter only keeps the rows matching all the conditions.
+// The conditions apply on the columns of the rows, whatever the case of their names.
+func (r *Rows) filter(where []parser.Condition) error {
+	if r.size == 0 {
+		return nil
+	}
+	index := make(map[string]int)
+	for p, name := range r.cols {
+		index[strings.ToLower(name)] = p
+	}
+	for _, c := range where {
+		if _, ok := index[strings.ToLower(c.Name())]; !ok {
+			return fmt.Errorf("%s (%v)", db.ErrUnknownColumn, c.Name())
+		}
+	}
+
+	var data [][]driver.Value
+	for _, v := range r.data {
+		ok := true
+		for _, c := range where {
+			if ok = matchCondition(fmt.Sprint(v[index[strings.ToLower(c.Name())]]), c); !ok {
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		data = append(data, v)
+	}
+	// Without matching rows, the columns are kept to describe the empty result set.
+	r.data, r.size = data, len(data)
+
+	return nil
+}
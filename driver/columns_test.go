@@ -0,0 +1,125 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	db "github.com/rvflash/awql-db"
+	parser "github.com/rvflash/awql-parser"
+)
+
+// TestCompatibleFields tests the function named compatibleFields.
+func TestCompatibleFields(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d, err := db.OpenConfig(db.Config{Version: "v201809", Dir: dir})
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	tb, err := d.Table("ACCOUNT_PERFORMANCE_REPORT")
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	var fieldTests = []struct {
+		cols    []string
+		in, out []string
+		err     bool
+	}{
+		{cols: []string{"Oops"}, err: true},
+		{cols: []string{"Date", "Oops"}, err: true},
+		{in: []string{"Date", "HourOfDay", "AllConversions", "ActiveViewCpm"}},
+		{
+			// The selected column is excluded, as the columns not compatible with it.
+			cols: []string{"HourOfDay"},
+			in:   []string{"Date", "ActiveViewCpm"},
+			out:  []string{"HourOfDay", "AllConversions", "AllConversionRate"},
+		},
+		{
+			// The columns whose the selected one is not compatible are also excluded.
+			cols: []string{"ConversionTypeName"},
+			in:   []string{"Date"},
+			out:  []string{"ConversionTypeName", "ActiveViewCpm", "ActiveViewCtr"},
+		},
+		{
+			cols: []string{"HourOfDay", "ConversionTypeName"},
+			in:   []string{"Date"},
+			out:  []string{"HourOfDay", "ConversionTypeName", "AllConversions", "ActiveViewCpm"},
+		},
+	}
+	for i, tt := range fieldTests {
+		fields, err := compatibleFields(tb, tt.cols)
+		if tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+			continue
+		}
+		names := make(map[string]bool)
+		for _, f := range fields {
+			names[f.Name()] = true
+		}
+		for _, name := range tt.in {
+			if !names[name] {
+				t.Errorf("%d. Expected %s in the compatible fields", i, name)
+			}
+		}
+		for _, name := range tt.out {
+			if names[name] {
+				t.Errorf("%d. Expected %s not in the compatible fields", i, name)
+			}
+		}
+	}
+}
+
+// TestRows_Filter tests the method named filter on Rows struct.
+func TestRows_Filter(t *testing.T) {
+	var rows = func() *Rows {
+		data := [][]driver.Value{
+			{"CampaignId", "Long", "YES"},
+			{"CampaignName", "String", "NO"},
+			{"Clicks", "Long", "NO"},
+		}
+		return &Rows{cols: []string{"Field", "Type", "Key"}, data: data, size: len(data), typed: true}
+	}
+	var filterTests = []struct {
+		where []parser.Condition
+		out   [][]driver.Value
+		err   bool
+	}{
+		{where: []parser.Condition{newCondition("Oops", opEqual, []string{"Long"}, true)}, err: true},
+		{
+			where: []parser.Condition{newCondition("type", opEqual, []string{"Long"}, true)},
+			out:   [][]driver.Value{{"CampaignId", "Long", "YES"}, {"Clicks", "Long", "NO"}},
+		},
+		{
+			where: []parser.Condition{
+				newCondition("Field", opStartsWith, []string{"Campaign"}, true),
+				newCondition("Key", opEqual, []string{"NO"}, true),
+			},
+			out: [][]driver.Value{{"CampaignName", "String", "NO"}},
+		},
+		// Without matching rows, the columns are kept.
+		{where: []parser.Condition{newCondition("Type", opEqual, []string{"Money"}, true)}},
+	}
+	for i, tt := range filterTests {
+		r := rows()
+		if err := r.filter(tt.where); tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+			continue
+		}
+		if tt.err {
+			continue
+		}
+		if !reflect.DeepEqual(r.data, tt.out) || r.size != len(tt.out) {
+			t.Errorf("%d. Expected %v, received %v", i, tt.out, r.data)
+		}
+		if cols := []string{"Field", "Type", "Key"}; !reflect.DeepEqual(r.cols, cols) || !r.typed {
+			t.Errorf("%d. Expected the columns %v, received %v", i, cols, r.cols)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package driver
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/rvflash/awql-db"
+	awql "github.com/rvflash/awql-driver"
+)
+
+// DsnScheme is the scheme of the URL-style data source name.
+const DsnScheme = "aawql"
+
+// List of cache backends.
+const (
+	CacheCSV  = "csv"
+	CacheNone = "none"
+)
+
+// List of backends of the API.
+const (
+	BackendAdwords   = "adwords"
+	BackendGoogleAds = "googleads"
+)
+
+// Default durations of the cache.
+const (
+	cacheTTL        = 10 * time.Minute
+	persistCacheTTL = 24 * time.Hour
+)
+
+// Names of the parameters of the URL-style data source name.
+const (
+	dsnDatabaseDir    = "db"
+	dsnViewsFile      = "views"
+	dsnCatalogFile    = "catalog"
+	dsnSchemaDir      = "schema"
+	dsnCacheDir       = "cache"
+	dsnCacheTTL       = "cache_ttl"
+	dsnCacheBackend   = "cache_backend"
+	dsnWithCache      = "with_cache"
+	dsnZero           = "zero"
+	dsnColumnHeader   = "column_header"
+	dsnRawEnums       = "raw_enums"
+	dsnSummary        = "summary"
+	dsnTyped          = "typed"
+	dsnEndpoint       = "endpoint"
+	dsnTokenEndpoint  = "token_endpoint"
+	dsnTimeout        = "timeout"
+	dsnTokenTimeout   = "token_timeout"
+	dsnDeveloperToken = "developer_token"
+	dsnAccessToken    = "access_token"
+	dsnClientID       = "client_id"
+	dsnClientSecret   = "client_secret"
+	dsnRefreshToken   = "refresh_token"
+	dsnBackend        = "backend"
+	dsnAdsEndpoint    = "ads_endpoint"
+	dsnAdsVersion     = "ads_version"
+	dsnLoginCustomer  = "login_customer_id"
+)
+
+// Config represents all the properties of a data source name.
+type Config struct {
+	// Adwords account and version of its API.
+	AdwordsID,
+	APIVersion string
+	// Path to the database directory, to the views file,
+	// to the read-only catalog of views, shared by a team for example,
+	// and to the directory of the schemas of the API versions not embedded in the tool.
+	DatabaseDir,
+	ViewsFile,
+	CatalogFile,
+	SchemaDir string
+	// Cache properties. Without TTL, the result sets are kept
+	// 24 hours if the cache is enabled, 10 minutes otherwise.
+	CacheDir,
+	CacheBackend string
+	CacheTTL  time.Duration
+	WithCache bool
+	// Adwords API options.
+	// The column header and the report summary are not returned as rows.
+	SupportsZeroImpressions,
+	IncludeColumnHeader,
+	UseRawEnumValues,
+	IncludeReportSummary bool
+	// Adwords API endpoints and timeouts. Default values are used if empty.
+	Endpoint,
+	TokenEndpoint string
+	Timeout,
+	TokenTimeout time.Duration
+	// Credentials.
+	DeveloperToken,
+	AccessToken,
+	ClientID,
+	ClientSecret,
+	RefreshToken string
+	// Returns each value with its Go type.
+	TypedValues bool
+	// API to request: the Adwords reports by default or the Google Ads API.
+	// The queries are translated in GAQL for the Google Ads API, sent to the endpoint
+	// of this version, with the manager account as login customer if set.
+	Backend,
+	AdsEndpoint,
+	AdsVersion,
+	LoginCustomerID string
+}
+
+// NewConfig returns a configuration with the default values for this Adwords account.
+func NewConfig(id string) *Config {
+	return &Config{
+		AdwordsID:    id,
+		APIVersion:   awql.APIVersion,
+		CacheBackend: CacheCSV,
+		Backend:      BackendAdwords,
+	}
+}
+
+// ParseDSN parses the data source name and returns its configuration.
+// Both formats are supported: the URL-style and the legacy one, delimited with colons and pipes.
+// @example aawql://123-456-7890/v201809?developer_token=dEve1op3er7okeN&access_token=ya29.AcC3s57okeN&typed=true
+func ParseDSN(dsn string) (*Config, error) {
+	if strings.HasPrefix(dsn, DsnScheme+"://") {
+		return parseURL(dsn)
+	}
+	return parseLegacy(dsn)
+}
+
+// FormatDSN returns the URL-style data source name of the configuration.
+// Only the options different from the default values are added.
+func FormatDSN(cfg *Config) string {
+	var setBool = func(v url.Values, key string, ok bool) {
+		if ok {
+			v.Set(key, strconv.FormatBool(ok))
+		}
+	}
+	var setDuration = func(v url.Values, key string, d time.Duration) {
+		if d > 0 {
+			v.Set(key, d.String())
+		}
+	}
+	var setString = func(v url.Values, key, s string) {
+		if s != "" {
+			v.Set(key, s)
+		}
+	}
+	v := url.Values{}
+	setString(v, dsnDatabaseDir, cfg.DatabaseDir)
+	setString(v, dsnViewsFile, cfg.ViewsFile)
+	setString(v, dsnCatalogFile, cfg.CatalogFile)
+	setString(v, dsnSchemaDir, cfg.SchemaDir)
+	setString(v, dsnCacheDir, cfg.CacheDir)
+	setDuration(v, dsnCacheTTL, cfg.CacheTTL)
+	if cfg.CacheBackend != CacheCSV {
+		setString(v, dsnCacheBackend, cfg.CacheBackend)
+	}
+	setBool(v, dsnWithCache, cfg.WithCache)
+	setBool(v, dsnZero, cfg.SupportsZeroImpressions)
+	setBool(v, dsnColumnHeader, cfg.IncludeColumnHeader)
+	setBool(v, dsnRawEnums, cfg.UseRawEnumValues)
+	setBool(v, dsnSummary, cfg.IncludeReportSummary)
+	setBool(v, dsnTyped, cfg.TypedValues)
+	setString(v, dsnEndpoint, cfg.Endpoint)
+	setString(v, dsnTokenEndpoint, cfg.TokenEndpoint)
+	setDuration(v, dsnTimeout, cfg.Timeout)
+	setDuration(v, dsnTokenTimeout, cfg.TokenTimeout)
+	setString(v, dsnDeveloperToken, cfg.DeveloperToken)
+	setString(v, dsnAccessToken, cfg.AccessToken)
+	setString(v, dsnClientID, cfg.ClientID)
+	setString(v, dsnClientSecret, cfg.ClientSecret)
+	setString(v, dsnRefreshToken, cfg.RefreshToken)
+	if cfg.Backend != BackendAdwords {
+		setString(v, dsnBackend, cfg.Backend)
+	}
+	setString(v, dsnAdsEndpoint, cfg.AdsEndpoint)
+	setString(v, dsnAdsVersion, cfg.AdsVersion)
+	setString(v, dsnLoginCustomer, cfg.LoginCustomerID)
+
+	u := url.URL{
+		Scheme:   DsnScheme,
+		Host:     cfg.AdwordsID,
+		Path:     "/" + cfg.APIVersion,
+		RawQuery: v.Encode(),
+	}
+	return u.String()
+}
+
+// Auth returns the authentication to use with these credentials, nil if there is none.
+func (cfg *Config) Auth() (*awql.Auth, error) {
+	switch {
+	case cfg.AccessToken != "":
+		return awql.NewAuthByToken(cfg.AccessToken)
+	case cfg.ClientID != "", cfg.ClientSecret != "", cfg.RefreshToken != "":
+		return awql.NewAuthByClient(cfg.ClientID, cfg.ClientSecret, cfg.RefreshToken)
+	}
+	return nil, nil
+}
+
+// Database returns the configuration to use to open the database of the tables.
+// Its paths are kept as they are, without being serialized again in a data source name.
+func (cfg *Config) Database() db.Config {
+	return db.Config{
+		Version:     cfg.APIVersion,
+		Dir:         cfg.DatabaseDir,
+		ViewsFile:   cfg.ViewsFile,
+		CatalogFile: cfg.CatalogFile,
+		SchemaDir:   cfg.SchemaDir,
+	}
+}
+
+// Opts returns the options to use to request the Adwords API.
+func (cfg *Config) Opts() *awql.Opts {
+	opts := awql.NewOpts(
+		cfg.APIVersion, cfg.SupportsZeroImpressions, !cfg.IncludeColumnHeader, cfg.UseRawEnumValues,
+	)
+	opts.SkipReportSummary = !cfg.IncludeReportSummary
+	opts.Endpoint = cfg.Endpoint
+	opts.TokenEndpoint = cfg.TokenEndpoint
+	opts.Timeout = cfg.Timeout
+	opts.TokenTimeout = cfg.TokenTimeout
+	opts.AdsEndpoint = cfg.AdsEndpoint
+	opts.AdsVersion = cfg.AdsVersion
+	opts.LoginCustomerID = cfg.LoginCustomerID
+
+	return opts
+}
+
+// UseGoogleAds returns true if the queries are sent to the Google Ads API.
+func (cfg *Config) UseGoogleAds() bool {
+	return cfg.Backend == BackendGoogleAds
+}
+
+// TTL returns the duration of the cache.
+func (cfg *Config) TTL() time.Duration {
+	switch {
+	case cfg.CacheTTL > 0:
+		return cfg.CacheTTL
+	case cfg.WithCache:
+		return persistCacheTTL
+	}
+	return cacheTTL
+}
+
+// parseLegacy parses a data source name delimited with colons and pipes.
+// @see DatabaseDir:CacheDir:WithCache[:TypedValues]|AdwordsId[:ApiVersion:SupportsZeroImpressions:SkipColumnHeader:UseRawEnumValues]|DeveloperToken[|AccessToken][|ClientId|ClientSecret|RefreshToken]
+func parseLegacy(dsn string) (*Config, error) {
+	// Validates the data source name.
+	src := strings.Split(dsn, awql.DsnSep)
+	switch len(src) {
+	case 3, 4, 6:
+	default:
+		return nil, ErrDsn
+	}
+	cfg := NewConfig("")
+	// As the Awql driver, the column header is only skipped on demand.
+	cfg.IncludeColumnHeader = true
+
+	// Extracts database directory, caching and typed values options.
+	d := strings.Split(src[0], awql.DsnOptSep)
+	switch len(d) {
+	case 4:
+		cfg.TypedValues, _ = strconv.ParseBool(d[3])
+		fallthrough
+	case 3:
+		cfg.WithCache, _ = strconv.ParseBool(d[2])
+		fallthrough
+	case 2:
+		cfg.CacheDir = d[1]
+		fallthrough
+	case 1:
+		cfg.DatabaseDir = d[0]
+	}
+
+	// Extracts the Adwords ID and its options.
+	d = strings.Split(src[1], awql.DsnOptSep)
+	switch len(d) {
+	case 5:
+		cfg.UseRawEnumValues, _ = strconv.ParseBool(d[4])
+		fallthrough
+	case 4:
+		skip, _ := strconv.ParseBool(d[3])
+		cfg.IncludeColumnHeader = !skip
+		fallthrough
+	case 3:
+		cfg.SupportsZeroImpressions, _ = strconv.ParseBool(d[2])
+		fallthrough
+	case 2:
+		if d[1] != "" {
+			cfg.APIVersion = d[1]
+		}
+		fallthrough
+	case 1:
+		cfg.AdwordsID = d[0]
+	}
+
+	// Extracts the credentials.
+	cfg.DeveloperToken = src[2]
+	switch len(src) {
+	case 4:
+		cfg.AccessToken = src[3]
+	case 6:
+		cfg.ClientID, cfg.ClientSecret, cfg.RefreshToken = src[3], src[4], src[5]
+	}
+	return cfg, nil
+}
+
+// parseURL parses a URL-style data source name.
+func parseURL(dsn string) (*Config, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, NewXError("invalid data source name", err)
+	}
+	cfg := NewConfig(u.Host)
+	if v := strings.Trim(u.Path, "/"); v != "" {
+		cfg.APIVersion = v
+	}
+	for key, val := range u.Query() {
+		if len(val) == 0 {
+			continue
+		}
+		s := val[len(val)-1]
+		switch key {
+		case dsnDatabaseDir:
+			cfg.DatabaseDir = s
+		case dsnViewsFile:
+			cfg.ViewsFile = s
+		case dsnCatalogFile:
+			cfg.CatalogFile = s
+		case dsnSchemaDir:
+			cfg.SchemaDir = s
+		case dsnCacheDir:
+			cfg.CacheDir = s
+		case dsnCacheTTL:
+			cfg.CacheTTL, err = time.ParseDuration(s)
+		case dsnCacheBackend:
+			if s != CacheCSV && s != CacheNone {
+				return nil, NewXError("invalid data source name", key)
+			}
+			cfg.CacheBackend = s
+		case dsnWithCache:
+			cfg.WithCache, err = strconv.ParseBool(s)
+		case dsnZero:
+			cfg.SupportsZeroImpressions, err = strconv.ParseBool(s)
+		case dsnColumnHeader:
+			cfg.IncludeColumnHeader, err = strconv.ParseBool(s)
+		case dsnRawEnums:
+			cfg.UseRawEnumValues, err = strconv.ParseBool(s)
+		case dsnSummary:
+			cfg.IncludeReportSummary, err = strconv.ParseBool(s)
+		case dsnTyped:
+			cfg.TypedValues, err = strconv.ParseBool(s)
+		case dsnEndpoint:
+			cfg.Endpoint = s
+		case dsnTokenEndpoint:
+			cfg.TokenEndpoint = s
+		case dsnTimeout:
+			cfg.Timeout, err = time.ParseDuration(s)
+		case dsnTokenTimeout:
+			cfg.TokenTimeout, err = time.ParseDuration(s)
+		case dsnDeveloperToken:
+			cfg.DeveloperToken = s
+		case dsnAccessToken:
+			cfg.AccessToken = s
+		case dsnClientID:
+			cfg.ClientID = s
+		case dsnClientSecret:
+			cfg.ClientSecret = s
+		case dsnRefreshToken:
+			cfg.RefreshToken = s
+		case dsnBackend:
+			if s != BackendAdwords && s != BackendGoogleAds {
+				return nil, NewXError("invalid data source name", key)
+			}
+			cfg.Backend = s
+		case dsnAdsEndpoint:
+			cfg.AdsEndpoint = s
+		case dsnAdsVersion:
+			cfg.AdsVersion = s
+		case dsnLoginCustomer:
+			cfg.LoginCustomerID = s
+		default:
+			return nil, NewXError("invalid data source name", key)
+		}
+		if err != nil {
+			return nil, NewXError("invalid data source name", key)
+		}
+	}
+	return cfg, nil
+}
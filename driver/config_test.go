@@ -0,0 +1,169 @@
+package driver_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	db "github.com/rvflash/awql-db"
+	"github.com/rvflash/awql/driver"
+)
+
+// TestFormatDSN tests the function named FormatDSN.
+func TestFormatDSN(t *testing.T) {
+	var dsnTests = []struct {
+		cfg *driver.Config
+		dsn string
+	}{
+		{cfg: driver.NewConfig(""), dsn: "aawql:///v201809"},
+		{cfg: driver.NewConfig("123-456-7890"), dsn: "aawql://123-456-7890/v201809"},
+		{
+			cfg: &driver.Config{
+				AdwordsID: "123-456-7890", APIVersion: "v201806", CacheBackend: driver.CacheCSV,
+				Backend: driver.BackendAdwords, TypedValues: true, WithCache: true, CacheTTL: time.Hour,
+				DeveloperToken: "dEve1op3er7okeN", AccessToken: "ya29.AcC3s57okeN",
+			},
+			dsn: "aawql://123-456-7890/v201806?access_token=ya29.AcC3s57okeN&cache_ttl=1h0m0s" +
+				"&developer_token=dEve1op3er7okeN&typed=true&with_cache=true",
+		},
+		{
+			cfg: &driver.Config{
+				AdwordsID: "123-456-7890", APIVersion: "v201809", CacheBackend: driver.CacheNone,
+				Backend: driver.BackendGoogleAds, LoginCustomerID: "987-654-3210",
+			},
+			dsn: "aawql://123-456-7890/v201809?backend=googleads&cache_backend=none&login_customer_id=987-654-3210",
+		},
+		// Paths with a colon or a pipe, escaped.
+		{
+			cfg: &driver.Config{
+				AdwordsID: "123-456-7890", APIVersion: "v201809", CacheBackend: driver.CacheCSV,
+				Backend: driver.BackendAdwords, DatabaseDir: "/a:b|c", ViewsFile: "/home/a|b/views.yml",
+			},
+			dsn: "aawql://123-456-7890/v201809?db=%2Fa%3Ab%7Cc&views=%2Fhome%2Fa%7Cb%2Fviews.yml",
+		},
+	}
+	for i, tt := range dsnTests {
+		if dsn := driver.FormatDSN(tt.cfg); dsn != tt.dsn {
+			t.Errorf("%d. Expected %v, received %v", i, tt.dsn, dsn)
+		}
+	}
+}
+
+// TestParseDSN tests the function named ParseDSN.
+func TestParseDSN(t *testing.T) {
+	var config = func(fn func(cfg *driver.Config)) *driver.Config {
+		cfg := driver.NewConfig("123-456-7890")
+		fn(cfg)
+		return cfg
+	}
+	var dsnTests = []struct {
+		dsn string
+		cfg *driver.Config
+		err bool
+	}{
+		// Errors.
+		{dsn: "", err: true},
+		{dsn: "123-456-7890", err: true},
+		{dsn: "aawql://123-456-7890/v201809?oops=true", err: true},
+		{dsn: "aawql://123-456-7890/v201809?typed=oops", err: true},
+		{dsn: "aawql://123-456-7890/v201809?cache_ttl=1", err: true},
+		{dsn: "aawql://123-456-7890/v201809?cache_backend=redis", err: true},
+		{dsn: "aawql://123-456-7890/v201809?backend=bing", err: true},
+		// URL-style.
+		{dsn: "aawql://123-456-7890", cfg: driver.NewConfig("123-456-7890")},
+		{
+			dsn: "aawql://123-456-7890/v201806?typed=true&zero=true&timeout=30s&developer_token=dEve1op3er7okeN",
+			cfg: config(func(cfg *driver.Config) {
+				cfg.APIVersion = "v201806"
+				cfg.TypedValues = true
+				cfg.SupportsZeroImpressions = true
+				cfg.Timeout = 30 * time.Second
+				cfg.DeveloperToken = "dEve1op3er7okeN"
+			}),
+		},
+		{
+			dsn: "aawql://123-456-7890/v201809?db=%2Fa%3Ab%7Cc&schema=%2Fs:t|u&catalog=/c|d:e.yml",
+			cfg: config(func(cfg *driver.Config) {
+				cfg.DatabaseDir = "/a:b|c"
+				cfg.SchemaDir = "/s:t|u"
+				cfg.CatalogFile = "/c|d:e.yml"
+			}),
+		},
+		// Legacy format.
+		{
+			dsn: "/data/base/dir:/cache/dir:true:true|123-456-7890:v201806:true:false:true|dEve1op3er7okeN|ya29.AcC3s57okeN",
+			cfg: config(func(cfg *driver.Config) {
+				cfg.DatabaseDir = "/data/base/dir"
+				cfg.CacheDir = "/cache/dir"
+				cfg.WithCache = true
+				cfg.TypedValues = true
+				cfg.APIVersion = "v201806"
+				cfg.SupportsZeroImpressions = true
+				cfg.IncludeColumnHeader = true
+				cfg.UseRawEnumValues = true
+				cfg.DeveloperToken = "dEve1op3er7okeN"
+				cfg.AccessToken = "ya29.AcC3s57okeN"
+			}),
+		},
+		// The column header is skipped with the fourth option of the Adwords ID.
+		{
+			dsn: "/data/base/dir|123-456-7890:v201809:false:true|dEve1op3er7okeN|ya29.AcC3s57okeN",
+			cfg: config(func(cfg *driver.Config) {
+				cfg.DatabaseDir = "/data/base/dir"
+				cfg.DeveloperToken = "dEve1op3er7okeN"
+				cfg.AccessToken = "ya29.AcC3s57okeN"
+			}),
+		},
+		// Without it, the column header is included.
+		{
+			dsn: "/data/base/dir|123-456-7890|dEve1op3er7okeN|c1i3n7iD|c1ien753cr37|1/R3Fr35h-70k3n",
+			cfg: config(func(cfg *driver.Config) {
+				cfg.DatabaseDir = "/data/base/dir"
+				cfg.IncludeColumnHeader = true
+				cfg.DeveloperToken = "dEve1op3er7okeN"
+				cfg.ClientID = "c1i3n7iD"
+				cfg.ClientSecret = "c1ien753cr37"
+				cfg.RefreshToken = "1/R3Fr35h-70k3n"
+			}),
+		},
+	}
+	for i, tt := range dsnTests {
+		cfg, err := driver.ParseDSN(tt.dsn)
+		if tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if !tt.err && !reflect.DeepEqual(cfg, tt.cfg) {
+			t.Errorf("%d. Expected %+v, received %+v", i, tt.cfg, cfg)
+		}
+	}
+}
+
+// TestConfig_RoundTrip tests that the configuration is kept by formatting then parsing its data source name.
+func TestConfig_RoundTrip(t *testing.T) {
+	cfg := driver.NewConfig("123-456-7890")
+	cfg.DatabaseDir = "/a:b|c"
+	cfg.ViewsFile = "/home/me:you/views|1.yml"
+	cfg.CatalogFile = "/team|catalog.yml"
+	cfg.SchemaDir = "C:\\awql|schema"
+	cfg.CacheDir = "/tmp/cache:1"
+	cfg.AccessToken = "ya29.A&c=c|3:s"
+	cfg.IncludeReportSummary = true
+
+	out, err := driver.ParseDSN(driver.FormatDSN(cfg))
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	if !reflect.DeepEqual(out, cfg) {
+		t.Errorf("Expected %+v, received %+v", cfg, out)
+	}
+	// The paths are given as they are to the database.
+	dbc := db.Config{
+		Version:     "v201809",
+		Dir:         "/a:b|c",
+		ViewsFile:   "/home/me:you/views|1.yml",
+		CatalogFile: "/team|catalog.yml",
+		SchemaDir:   "C:\\awql|schema",
+	}
+	if d := out.Database(); d != dbc {
+		t.Errorf("Expected %+v, received %+v", dbc, d)
+	}
+}
@@ -0,0 +1,107 @@
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"os"
+	"path/filepath"
+	"sync"
+
+	db "github.com/rvflash/awql-db"
+	awql "github.com/rvflash/awql-driver"
+	cache "github.com/rvflash/csv-cache"
+)
+
+// Connector represents a data source name with its database and its cache.
+// Both are loaded once and shared by all the connections opened by the connector.
+// The warnings raised by the loading of the database are given to the first connection.
+// It implements the driver.Connector interface.
+type Connector struct {
+	cfg   *Config
+	db    *db.Database
+	fc    *cache.Cache
+	sc    *cache.Cache
+	warns []error
+	mu    sync.RWMutex
+}
+
+// NewConnector loads the database and initializes the cache of this configuration.
+// @see ParseDSN to get the configuration of a data source name.
+func NewConnector(cfg *Config) (*Connector, error) {
+	if cfg == nil || cfg.AdwordsID == "" {
+		return nil, driver.ErrBadConn
+	}
+	// Initializes the cache to save result sets inside.
+	// Without backend, the cache has no directory and ignores all the items.
+	var dir string
+	if cfg.CacheBackend != CacheNone {
+		dir = cfg.CacheDir
+	}
+	c := cache.New(dir, cfg.TTL())
+	if cfg.WithCache {
+		// Cache enabled, only removes outdated files.
+		c.FlushAll()
+	} else {
+		// Cache disabled, removes all existing file caches.
+		c.DeleteAll()
+	}
+	// Initializes the storage of the materialized views, kept whatever the cache mode.
+	var sdir string
+	if dir != "" {
+		sdir = filepath.Join(dir, snapshotDir)
+		if err := os.MkdirAll(sdir, 0755); err != nil {
+			sdir = ""
+		}
+	}
+	sc := cache.New(sdir, snapshotTTL)
+
+	// Loads all information about the database.
+	awqlDb, err := db.OpenConfig(cfg.Database())
+	if err != nil {
+		return nil, err
+	}
+	return &Connector{cfg: cfg, db: awqlDb, fc: c, sc: sc, warns: awqlDb.Warnings()}, nil
+}
+
+// Connect returns a new connection to the database.
+func (c *Connector) Connect(_ context.Context) (driver.Conn, error) {
+	auth, err := c.cfg.Auth()
+	if err != nil {
+		return nil, err
+	}
+	// Wraps the Awql driver, its options are updated with the settings of the session.
+	opts := c.cfg.Opts()
+	conn, err := awql.NewConn(c.cfg.AdwordsID, c.cfg.DeveloperToken, auth, opts)
+	if err != nil {
+		return nil, err
+	}
+	// The warnings of the loading of the database are these of the first statement of the first connection.
+	c.mu.Lock()
+	wl := &warnings{list: c.warns}
+	c.warns = nil
+	c.mu.Unlock()
+
+	return &Conn{
+		cn:    conn,
+		db:    c.db,
+		fc:    c.fc,
+		sc:    c.sc,
+		wl:    wl,
+		ss:    newSession(c.cfg),
+		mu:    &c.mu,
+		opts:  opts,
+		id:    c.cfg.AdwordsID,
+		typed: c.cfg.TypedValues,
+		ads:   c.cfg.UseGoogleAds(),
+	}, nil
+}
+
+// Database returns the database loaded by the connector.
+func (c *Connector) Database() *db.Database {
+	return c.db
+}
+
+// Driver returns the underlying driver of the connector.
+func (c *Connector) Driver() driver.Driver {
+	return &AdvancedDriver{}
+}
@@ -0,0 +1,141 @@
+package driver_test
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/rvflash/awql/driver"
+)
+
+// openDB returns a database using a connector without cache, with its database in a temporary directory.
+func openDB(t *testing.T) (*sql.DB, func()) {
+	dir, err := ioutil.TempDir("", "awql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := driver.NewConfig("123-456-7890")
+	cfg.DatabaseDir = dir
+	cfg.CacheBackend = driver.CacheNone
+	cfg.DeveloperToken = "dEve1op3er7okeN"
+	cfg.AccessToken = "ya29.AcC3s57okeN"
+	c, err := driver.NewConnector(cfg)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	d := sql.OpenDB(c)
+	return d, func() {
+		d.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// TestConnector_Warnings tests that the warnings of a connection are not seen by the other ones.
+func TestConnector_Warnings(t *testing.T) {
+	d, done := openDB(t)
+	defer done()
+
+	ctx := context.Background()
+	c1, err := d.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	defer c1.Close()
+	c2, err := d.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	defer c2.Close()
+
+	// Explains a statement with a redundant column, without requesting Adwords.
+	rs, err := c1.QueryContext(ctx, "EXPLAIN SELECT CampaignId, CampaignId FROM CAMPAIGN_PERFORMANCE_REPORT")
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	rs.Close()
+
+	var warningTests = []struct {
+		cn *sql.Conn
+		n  int
+	}{
+		{cn: c1, n: 1},
+		{cn: c2, n: 0},
+	}
+	for i, tt := range warningTests {
+		var n int
+		if err := tt.cn.QueryRowContext(ctx, "SHOW COUNT(*) WARNINGS").Scan(&n); err != nil {
+			t.Errorf("%d. Expected no error, received %s", i, err)
+		} else if n != tt.n {
+			t.Errorf("%d. Expected %d warnings, received %d", i, tt.n, n)
+		}
+	}
+}
+
+// TestConnector_Session tests that the session of a connection is not changed by the other ones.
+func TestConnector_Session(t *testing.T) {
+	d, done := openDB(t)
+	defer done()
+
+	ctx := context.Background()
+	c1, err := d.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	defer c1.Close()
+	c2, err := d.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	defer c2.Close()
+
+	for _, q := range []string{"SET raw_enum_values = ON", "SET include_summary = ON", "SET @id = 123"} {
+		if _, err := c1.ExecContext(ctx, q); err != nil {
+			t.Fatalf("Expected no error with %s, received %s", q, err)
+		}
+	}
+	// variables returns the variables of the session of the connection.
+	var variables = func(cn *sql.Conn) map[string]string {
+		rs, err := cn.QueryContext(ctx, "SHOW VARIABLES")
+		if err != nil {
+			t.Fatalf("Expected no error, received %s", err)
+		}
+		defer rs.Close()
+		vars := make(map[string]string)
+		for rs.Next() {
+			var name, value string
+			if err := rs.Scan(&name, &value); err != nil {
+				t.Fatalf("Expected no error, received %s", err)
+			}
+			vars[name] = value
+		}
+		return vars
+	}
+	var sessionTests = []struct {
+		cn   *sql.Conn
+		vars map[string]string
+	}{
+		{
+			cn: c1,
+			vars: map[string]string{
+				driver.VarAPIVersion: "v201809", driver.VarCache: "OFF", driver.VarIncludeSummary: "ON",
+				driver.VarRawEnumValues: "ON", driver.VarZeroImpressions: "OFF", "@id": "123",
+			},
+		},
+		{
+			cn: c2,
+			vars: map[string]string{
+				driver.VarAPIVersion: "v201809", driver.VarCache: "OFF", driver.VarIncludeSummary: "OFF",
+				driver.VarRawEnumValues: "OFF", driver.VarZeroImpressions: "OFF",
+			},
+		},
+	}
+	for i, tt := range sessionTests {
+		if vars := variables(tt.cn); !reflect.DeepEqual(vars, tt.vars) {
+			t.Errorf("%d. Expected %v, received %v", i, tt.vars, vars)
+		}
+	}
+}
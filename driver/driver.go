@@ -1,12 +1,11 @@
 package driver
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"io"
-	"strconv"
-	"strings"
-	"time"
+	"sync"
 
 	db "github.com/rvflash/awql-db"
 	awql "github.com/rvflash/awql-driver"
@@ -25,76 +24,48 @@ func init() {
 }
 
 // Open returns a new connection to the database.
-// @see DatabaseDir:CacheDir:WithCache|AdwordsId[:ApiVersion:SupportsZeroImpressions]|DeveloperToken[|ClientId][|ClientSecret][|RefreshToken]
-// @example /data/base/dir:/cache/dir:false|123-456-7890:v201607:true|dEve1op3er7okeN|1234567890-c1i3n7iD.com|c1ien753cr37|1/R3Fr35h-70k3n
+// @see ParseDSN for how the DSN string is formatted.
+// @example aawql://123-456-7890/v201809?db=/data/base/dir&cache=/cache/dir&typed=true&developer_token=dEve1op3er7okeN&access_token=ya29.AcC3s57okeN
+//
+// The legacy format, delimited with colons and pipes, is still supported.
+// @example /data/base/dir:/cache/dir:false:true|123-456-7890:v201607:true|dEve1op3er7okeN|1234567890-c1i3n7iD.com|c1ien753cr37|1/R3Fr35h-70k3n
+//
+// With typed values, each value is returned with its Go type (int64, float64, time.Time, string or nil)
+// instead of the string representation used by Google in its reports.
+//
+// Each call loads the database and the cache, OpenConnector should be preferred to share them.
 func (d *AdvancedDriver) Open(dsn string) (driver.Conn, error) {
-	// Extracts database directory and caching option.
-	var dbCache = func(s string) (db, cache string, caching bool) {
-		d := strings.Split(s, awql.DsnOptSep)
-		switch len(d) {
-		case 3:
-			caching, _ = strconv.ParseBool(d[2])
-			fallthrough
-		case 2:
-			cache = d[1]
-			fallthrough
-		case 1:
-			db = d[0]
-		}
-		return
-	}
-	// Validates the data source name.
-	src := strings.SplitN(dsn, awql.DsnSep, 2)
-	if len(src) != 2 {
-		return nil, driver.ErrBadConn
-	}
-	dbd, cached, wc := dbCache(src[0])
-
-	// Initializes the cache to save result sets inside.
-	ttl := 10 * time.Minute
-	if wc {
-		ttl = 24 * time.Hour
-	}
-	c := cache.New(cached, ttl)
-	if wc {
-		// Cache enabled, only removes outdated files.
-		c.FlushAll()
-	} else {
-		// Cache disabled, removes all existing file caches.
-		c.DeleteAll()
-	}
-
-	// Wraps the Awql driver.
-	dd := &awql.Driver{}
-	conn, err := dd.Open(src[1])
+	c, err := d.OpenConnector(dsn)
 	if err != nil {
 		return nil, err
 	}
+	return c.Connect(context.Background())
+}
 
-	// Gets the API version to use.
-	var idVersion = func(s string) (string, string) {
-		d := strings.Split(strings.Split(s, awql.DsnSep)[0], awql.DsnOptSep)
-		if len(d) > 1 {
-			return d[0], d[1]
-		}
-		return d[0], awql.APIVersion
-	}
-	id, v := idVersion(src[1])
-
-	// Loads all information about the database.
-	awqlDb, err := db.Open(v + "|" + dbd)
+// OpenConnector returns a connector that loads once the database and the cache of this data source name.
+// It implements the driver.DriverContext interface.
+func (d *AdvancedDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	cfg, err := ParseDSN(dsn)
 	if err != nil {
 		return nil, err
 	}
-	return &Conn{cn: conn.(*awql.Conn), fc: c, db: awqlDb, id: id}, nil
+	return NewConnector(cfg)
 }
 
 // Conn represents a connection to a database and implements driver.Conn.
+// It also implements the driver.Pinger and driver.NamedValueChecker interfaces.
 type Conn struct {
-	cn *awql.Conn
-	db *db.Database
-	fc *cache.Cache
-	id string
+	cn    *awql.Conn
+	db    *db.Database
+	fc    *cache.Cache
+	sc    *cache.Cache
+	wl    *warnings
+	ss    *session
+	mu    *sync.RWMutex
+	opts  *awql.Opts
+	id    string
+	typed bool
+	ads   bool
 }
 
 // Close marks this connection as no longer in use.
@@ -107,13 +78,45 @@ func (c *Conn) Begin() (driver.Tx, error) {
 	return c.cn.Begin()
 }
 
+// Ping verifies the credentials and the account used to connect to the Adwords API,
+// or to the Google Ads API with this backend.
+func (c *Conn) Ping(ctx context.Context) error {
+	if c.ads {
+		return c.cn.PingAds(ctx)
+	}
+	return c.cn.Ping(ctx)
+}
+
+// CheckNamedValue accepts the slices of values to expand them as list.
+// The other values are converted by the default converter.
+// It implements the driver.NamedValueChecker interface.
+func (c *Conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if !isList(nv.Value) {
+		return driver.ErrSkip
+	}
+	return nil
+}
+
 // Prepare returns a prepared statement, bound to this connection.
 func (c *Conn) Prepare(q string) (driver.Stmt, error) {
 	if q == "" {
 		// No query to prepare.
 		return nil, io.EOF
 	}
-	return &Stmt{si: &awql.Stmt{Db: c.cn, SrcQuery: q}, db: c.db, fc: c.fc, id: c.id}, nil
+	return &Stmt{
+		si:    &awql.Stmt{Db: c.cn, SrcQuery: q},
+		query: q,
+		db:    c.db,
+		fc:    c.fc,
+		sc:    c.sc,
+		wl:    c.wl,
+		ss:    c.ss,
+		mu:    c.mu,
+		opts:  c.opts,
+		id:    c.id,
+		typed: c.typed,
+		ads:   c.ads,
+	}, nil
 }
 
 // Result is the result of a query execution.
@@ -6,12 +6,16 @@ import (
 	awql "github.com/rvflash/awql-driver"
 )
 
-// Dsn represents a data source name.
+// Dsn represents a data source name in the legacy format.
+//
+// Deprecated: Paths or tokens with a colon or a pipe break this format,
+// use Config with FormatDSN instead.
 type Dsn struct {
 	DatabaseDir,
 	CacheDir,
 	Src string
-	WithCache bool
+	WithCache,
+	TypedValues bool
 }
 
 // NewDsn returns a new instance of Dsn.
@@ -25,11 +29,12 @@ func NewDsn(db, src, cache string, cached bool) *Dsn {
 }
 
 // String outputs the data source name as string.
-// /data/base/dir:/cache/dir:false|123-456-7890:v201607|dEve1op3er7okeN|1234567890-c1i3n7iD.com|c1ien753cr37|1/R3Fr35h-70k3n
+// /data/base/dir:/cache/dir:false:true|123-456-7890:v201607|dEve1op3er7okeN|1234567890-c1i3n7iD.com|c1ien753cr37|1/R3Fr35h-70k3n
 func (d *Dsn) String() (s string) {
 	s = d.DatabaseDir
 	s += awql.DsnOptSep + d.CacheDir
 	s += awql.DsnOptSep + strconv.FormatBool(d.WithCache)
+	s += awql.DsnOptSep + strconv.FormatBool(d.TypedValues)
 	s += awql.DsnSep + d.Src
 
 	return
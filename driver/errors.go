@@ -2,20 +2,29 @@ package driver
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
 // Error messages.
 var (
+	ErrBinding         = NewError("invalid binding")
+	ErrDisjoint        = NewError("conditions excluding each other")
+	ErrDsn             = NewError("invalid data source name")
 	ErrMultipleQueries = NewError("unsupported multi queries")
 	ErrQuery           = NewError("unsupported query")
 	ErrOutRange        = NewError("out of scope of view")
+	ErrParameter       = NewError("invalid parameter")
+	ErrSnapshot        = NewError("materialized view not stored")
 )
 
 // Error represents a internal error.
+// It may be located in the query and propose a fix.
 type Error struct {
-	s string
-	a interface{}
+	s    string
+	a    interface{}
+	pos  int
+	hint string
 }
 
 // NewError returns an error of type Driver with the given text.
@@ -28,12 +37,30 @@ func NewXError(text string, arg interface{}) error {
 	return &Error{s: formatError(text), a: arg}
 }
 
+// NewPosError returns an error of type Driver with the given text, located at this position in the query.
+// The hint is proposed as fix, if not empty.
+func NewPosError(text string, arg interface{}, pos int, hint string) error {
+	return &Error{s: formatError(text), a: arg, pos: pos, hint: hint}
+}
+
 // Error outputs a query error message.
 func (e *Error) Error() string {
+	s := "DriverError." + e.s
 	if e.a != nil {
-		return fmt.Sprintf("DriverError.%v (%v)", e.s, e.a)
+		s += fmt.Sprintf(" (%v)", e.a)
+	}
+	if e.pos > 0 {
+		s += " at position " + strconv.Itoa(e.pos)
 	}
-	return "DriverError." + e.s
+	if e.hint != "" {
+		s += ", did you mean " + e.hint + "?"
+	}
+	return s
+}
+
+// Position returns the position of the error in the query, 0 if unknown.
+func (e *Error) Position() int {
+	return e.pos
 }
 
 // formatError returns a string in upper case with underscore instead of space.
@@ -0,0 +1,166 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"strconv"
+	"strings"
+
+	db "github.com/rvflash/awql-db"
+	parser "github.com/rvflash/awql-parser"
+)
+
+// ExplainStmt represents an Explain statement.
+type ExplainStmt struct {
+	*Stmt
+}
+
+// NewExplainStmt returns an instance of ExplainStmt.
+// It implements Queryer interface.
+func NewExplainStmt(stmt *Stmt) Queryer {
+	return &ExplainStmt{stmt}
+}
+
+// Query executes an Explain query.
+// It returns the execution plan of the select statement, without requesting Adwords.
+func (s *ExplainStmt) Query() (driver.Rows, error) {
+	// Casts statement.
+	stmt := s.p.(parser.ExplainStmt)
+
+	// Explains the select statement as it would be executed.
+	s.p = stmt.Explained()
+
+	return (&SelectStmt{Stmt: s.Stmt, explain: true}).Query()
+}
+
+// queryPlan contains what is learnt while preparing a select statement to explain its execution.
+type queryPlan struct {
+	views    []string
+	during   []string
+	mv       db.DataTable
+	skey     string
+	outRange bool
+	disjoint bool
+	schema   bool
+}
+
+// plan returns the execution plan of the statement, ready to send to Adwords, one step by row.
+// The plan lists the views unwrapped, the query sent (in GAQL with the Google Ads API), the date range,
+// the use of the cache, the clauses applied locally, the warnings like the redundant columns ignored and the number of calls to Adwords.
+func (s *SelectStmt) plan(stmt *parser.SelectStatement, p queryPlan) (driver.Rows, error) {
+	var data [][]driver.Value
+
+	// add appends a step of the plan with its detail.
+	var add = func(step, detail string) {
+		data = append(data, []driver.Value{step, detail})
+	}
+	// localClauses returns the clauses of the statement not supported by Adwords, applied on the records.
+	var localClauses = func() string {
+		var clauses []string
+		if (p.mv != nil || p.schema) && len(stmt.Where) > 0 {
+			clauses = append(clauses, "WHERE")
+		}
+		var aggregate, distinct, computed bool
+		for _, c := range stmt.Columns() {
+			_, ok := c.UseFunction()
+			aggregate = aggregate || ok
+			distinct = distinct || c.Distinct()
+			_, ok = c.Expression()
+			computed = computed || ok
+		}
+		if aggregate {
+			clauses = append(clauses, "aggregates")
+		}
+		if distinct {
+			clauses = append(clauses, "DISTINCT")
+		}
+		if computed {
+			clauses = append(clauses, "computed columns")
+		}
+		if len(stmt.GroupList()) > 0 {
+			clauses = append(clauses, "GROUP BY")
+		}
+		if len(stmt.OrderList()) > 0 {
+			clauses = append(clauses, "ORDER BY")
+		}
+		if _, ok := stmt.PageSize(); ok {
+			clauses = append(clauses, "LIMIT")
+		}
+		if len(clauses) == 0 {
+			return "none"
+		}
+		return strings.Join(clauses, ", ")
+	}
+	// duringRange returns the date range of the statement, with the dates of its literal.
+	var duringRange = func(d []string) string {
+		switch len(d) {
+		case 1:
+			r := duringDates(d[0])
+			return d[0] + " (" + r[0] + " - " + r[1] + ")"
+		case 2:
+			return d[0] + " - " + d[1]
+		}
+		return ""
+	}
+
+	if len(p.views) > 0 {
+		views := strings.Join(p.views, " -> ")
+		if p.mv != nil {
+			views += " (materialized)"
+		}
+		add("View", views)
+	}
+	var calls int
+	switch {
+	case p.outRange:
+		add("Query", "none, out of the date range of the view")
+	case p.disjoint:
+		add("Query", "none, the conditions of the view and the statement exclude each other")
+	case p.schema:
+		add("Source", stmt.SourceName())
+		add("Query", "none, built with the tables of the database")
+	case p.mv != nil:
+		add("Source", "snapshot of "+p.mv.SourceName())
+		if d := duringRange(p.mv.SourceQuery().DuringList()); d != "" {
+			add("During", d)
+		}
+		if records, err := s.sc.Get(p.skey); err != nil || len(records) == 0 || s.isOutdated(p.mv, p.skey) {
+			// The view is refreshed before querying its snapshot.
+			add("Query", "SELECT * FROM "+p.mv.SourceName())
+			add("Snapshot", "MISS ("+p.skey+")")
+			calls = 1
+		} else {
+			add("Snapshot", "HIT ("+p.skey+")")
+		}
+	default:
+		if r, ok := gaqlResources[stmt.SourceName()]; ok && s.ads {
+			add("Source", stmt.SourceName()+" (Google Ads resource "+r.name+")")
+		} else {
+			add("Source", stmt.SourceName())
+		}
+		add("Query", s.si.SrcQuery)
+		if d := duringRange(stmt.DuringList()); d != "" {
+			if r := duringRange(p.during); r != "" && r != d {
+				d += ", requested " + r
+			}
+			add("During", d)
+		}
+		if s.fc.Exists(s.Hash()) {
+			add("Cache", "HIT ("+s.Hash()+")")
+		} else {
+			add("Cache", "MISS ("+s.Hash()+")")
+			calls = 1
+		}
+	}
+	add("Local", localClauses())
+	for _, w := range s.warns {
+		add("Warning", w.Error())
+	}
+	add("API calls", strconv.Itoa(calls))
+
+	return &Rows{
+		cols:  []string{"Step", "Detail"},
+		data:  data,
+		size:  len(data),
+		typed: s.typed,
+	}, nil
+}
@@ -0,0 +1,577 @@
+package driver
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/rvflash/awql-db"
+	awql "github.com/rvflash/awql-driver"
+	parser "github.com/rvflash/awql-parser"
+)
+
+// gaqlDateFormat is the format of the date to use in Google Ads API.
+const gaqlDateFormat = "2006-01-02"
+
+// gaqlResource represents a resource of the Google Ads API and the fields
+// to use instead of the columns of the legacy report.
+type gaqlResource struct {
+	name   string
+	fields map[string]string
+}
+
+// gaqlResources maps the legacy reports supported to their Google Ads API resource.
+// The columns not listed by the resource are searched in the common fields.
+// @see https://developers.google.com/google-ads/api/docs/migration/mapping
+var gaqlResources = map[string]gaqlResource{
+	"ACCOUNT_PERFORMANCE_REPORT": {name: "customer"},
+	"CAMPAIGN_PERFORMANCE_REPORT": {
+		name: "campaign",
+		fields: map[string]string{
+			"AdvertisingChannelType": "campaign.advertising_channel_type",
+			"Amount":                 "campaign_budget.amount_micros",
+			"BudgetId":               "campaign_budget.id",
+			"EndDate":                "campaign.end_date",
+			"ServingStatus":          "campaign.serving_status",
+			"StartDate":              "campaign.start_date",
+		},
+	},
+	"ADGROUP_PERFORMANCE_REPORT": {
+		name: "ad_group",
+		fields: map[string]string{
+			"CpcBid":    "ad_group.cpc_bid_micros",
+			"CpmBid":    "ad_group.cpm_bid_micros",
+			"CpvBid":    "ad_group.cpv_bid_micros",
+			"TargetCpa": "ad_group.target_cpa_micros",
+		},
+	},
+	"AD_PERFORMANCE_REPORT": {
+		name: "ad_group_ad",
+		fields: map[string]string{
+			"AdType":                 "ad_group_ad.ad.type",
+			"CombinedApprovalStatus": "ad_group_ad.policy_summary.approval_status",
+			"Headline":               "ad_group_ad.ad.text_ad.headline",
+			"HeadlinePart1":          "ad_group_ad.ad.expanded_text_ad.headline_part1",
+			"HeadlinePart2":          "ad_group_ad.ad.expanded_text_ad.headline_part2",
+			"Id":                     "ad_group_ad.ad.id",
+			"Status":                 "ad_group_ad.status",
+		},
+	},
+	"KEYWORDS_PERFORMANCE_REPORT": {
+		name: "keyword_view",
+		fields: map[string]string{
+			"ApprovalStatus":      "ad_group_criterion.approval_status",
+			"CpcBid":              "ad_group_criterion.cpc_bid_micros",
+			"Criteria":            "ad_group_criterion.keyword.text",
+			"FirstPageCpc":        "ad_group_criterion.position_estimates.first_page_cpc_micros",
+			"FirstPositionCpc":    "ad_group_criterion.position_estimates.first_position_cpc_micros",
+			"Id":                  "ad_group_criterion.criterion_id",
+			"IsNegative":          "ad_group_criterion.negative",
+			"KeywordMatchType":    "ad_group_criterion.keyword.match_type",
+			"QualityScore":        "ad_group_criterion.quality_info.quality_score",
+			"Status":              "ad_group_criterion.status",
+			"SystemServingStatus": "ad_group_criterion.system_serving_status",
+			"TopOfPageCpc":        "ad_group_criterion.position_estimates.top_of_page_cpc_micros",
+		},
+	},
+	"SEARCH_QUERY_PERFORMANCE_REPORT": {
+		name: "search_term_view",
+		fields: map[string]string{
+			"KeywordTextMatchingQuery":  "segments.keyword.info.text",
+			"Query":                     "search_term_view.search_term",
+			"QueryMatchTypeWithVariant": "segments.search_term_match_type",
+			"QueryTargetingStatus":      "search_term_view.status",
+		},
+	},
+	"BUDGET_PERFORMANCE_REPORT": {
+		name: "campaign_budget",
+		fields: map[string]string{
+			"Amount":                   "campaign_budget.amount_micros",
+			"BudgetId":                 "campaign_budget.id",
+			"BudgetName":               "campaign_budget.name",
+			"BudgetReferenceCount":     "campaign_budget.reference_count",
+			"BudgetStatus":             "campaign_budget.status",
+			"DeliveryMethod":           "campaign_budget.delivery_method",
+			"HasRecommendedBudget":     "campaign_budget.has_recommended_budget",
+			"IsBudgetExplicitlyShared": "campaign_budget.explicitly_shared",
+			"Period":                   "campaign_budget.period",
+			"RecommendedBudgetAmount":  "campaign_budget.recommended_budget_amount_micros",
+			"TotalAmount":              "campaign_budget.total_amount_micros",
+		},
+	},
+	"GEO_PERFORMANCE_REPORT": {
+		name: "geographic_view",
+		fields: map[string]string{
+			"CityCriteriaId":         "segments.geo_target_city",
+			"CountryCriteriaId":      "geographic_view.country_criterion_id",
+			"LocationType":           "geographic_view.location_type",
+			"MetroCriteriaId":        "segments.geo_target_metro",
+			"MostSpecificCriteriaId": "segments.geo_target_most_specific_location",
+			"RegionCriteriaId":       "segments.geo_target_region",
+		},
+	},
+}
+
+// gaqlFields maps the columns shared by the legacy reports to their Google Ads API field.
+var gaqlFields = map[string]string{
+	// Customer
+	"AccountCurrencyCode":    "customer.currency_code",
+	"AccountDescriptiveName": "customer.descriptive_name",
+	"AccountTimeZone":        "customer.time_zone",
+	"ExternalCustomerId":     "customer.id",
+	// Campaign and ad group
+	"AdGroupId":      "ad_group.id",
+	"AdGroupName":    "ad_group.name",
+	"AdGroupStatus":  "ad_group.status",
+	"CampaignId":     "campaign.id",
+	"CampaignName":   "campaign.name",
+	"CampaignStatus": "campaign.status",
+	// Segments
+	"AdNetworkType1": "segments.ad_network_type",
+	"Date":           "segments.date",
+	"DayOfWeek":      "segments.day_of_week",
+	"Device":         "segments.device",
+	"HourOfDay":      "segments.hour",
+	"Month":          "segments.month",
+	"MonthOfYear":    "segments.month_of_year",
+	"Quarter":        "segments.quarter",
+	"Slot":           "segments.slot",
+	"Week":           "segments.week",
+	"Year":           "segments.year",
+	// Metrics
+	"AllConversionRate":      "metrics.all_conversions_from_interactions_rate",
+	"AllConversions":         "metrics.all_conversions",
+	"AllConversionValue":     "metrics.all_conversions_value",
+	"AverageCost":            "metrics.average_cost",
+	"AverageCpc":             "metrics.average_cpc",
+	"AverageCpm":             "metrics.average_cpm",
+	"Clicks":                 "metrics.clicks",
+	"ConversionRate":         "metrics.conversions_from_interactions_rate",
+	"Conversions":            "metrics.conversions",
+	"ConversionValue":        "metrics.conversions_value",
+	"Cost":                   "metrics.cost_micros",
+	"CostPerAllConversion":   "metrics.cost_per_all_conversions",
+	"CostPerConversion":      "metrics.cost_per_conversion",
+	"Ctr":                    "metrics.ctr",
+	"Impressions":            "metrics.impressions",
+	"InteractionRate":        "metrics.interaction_rate",
+	"Interactions":           "metrics.interactions",
+	"VideoViewRate":          "metrics.video_view_rate",
+	"VideoViews":             "metrics.video_views",
+	"ViewThroughConversions": "metrics.view_through_conversions",
+}
+
+// gaqlRates lists the fields returned as ratio by the Google Ads API and as percentage in the legacy reports.
+var gaqlRates = map[string]bool{
+	"metrics.all_conversions_from_interactions_rate": true,
+	"metrics.conversions_from_interactions_rate":     true,
+	"metrics.ctr":              true,
+	"metrics.interaction_rate": true,
+	"metrics.video_view_rate":  true,
+}
+
+// gaqlMicros lists the amounts in micros returned as double by the Google Ads API and as integer in the legacy reports.
+var gaqlMicros = map[string]bool{
+	"metrics.average_cost":             true,
+	"metrics.average_cpc":              true,
+	"metrics.average_cpm":              true,
+	"metrics.cost_per_all_conversions": true,
+	"metrics.cost_per_conversion":      true,
+}
+
+// gaqlEnum represents the values of an enum kind of the legacy reports in the Google Ads API.
+// The values of the API are the legacy ones, except these listed as renamed.
+// A legacy value being the target of a renaming is only matched by the values of the API renamed in it.
+type gaqlEnum struct {
+	// renamed maps the values of the Google Ads API to the legacy ones, when they differ.
+	renamed map[string]string
+	// display maps the legacy values to these displayed by the reports without raw enum values.
+	display map[string]string
+}
+
+// legacy returns the legacy value of the value of the Google Ads API.
+func (e gaqlEnum) legacy(v string) string {
+	if l, ok := e.renamed[v]; ok {
+		return l
+	}
+	return v
+}
+
+// values returns the values of the Google Ads API matching the legacy value, sorted.
+func (e gaqlEnum) values(v string) (list []string) {
+	for g, l := range e.renamed {
+		if l == v {
+			list = append(list, g)
+		}
+	}
+	if len(list) == 0 {
+		return []string{v}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// gaqlEnums maps the enum kinds of the legacy columns, as named in the schema, to their values in the Google Ads API.
+// The columns of an enum kind not listed are not supported.
+var gaqlEnums = map[string]gaqlEnum{
+	"AdGroupStatus": {
+		renamed: map[string]string{"UNSPECIFIED": "UNKNOWN", "UNKNOWN": "UNKNOWN"},
+		display: map[string]string{"UNKNOWN": "unknown", "ENABLED": "enabled", "PAUSED": "paused", "REMOVED": "removed"},
+	},
+	"AdNetworkType1": {
+		renamed: map[string]string{
+			"UNSPECIFIED": "UNKNOWN", "UNKNOWN": "UNKNOWN", "SEARCH": "SEARCH", "SEARCH_PARTNERS": "SEARCH",
+		},
+		display: map[string]string{
+			"UNKNOWN": "unknown", "SEARCH": "Search Network", "CONTENT": "Display Network",
+			"YOUTUBE_SEARCH": "YouTube Search", "YOUTUBE_WATCH": "YouTube Videos", "MIXED": "Cross-network",
+		},
+	},
+	"AdvertisingChannelType": {
+		renamed: map[string]string{"UNSPECIFIED": "UNKNOWN", "UNKNOWN": "UNKNOWN", "SMART": "EXPRESS"},
+		display: map[string]string{
+			"UNKNOWN": "unknown", "SEARCH": "Search", "DISPLAY": "Display", "SHOPPING": "Shopping",
+			"VIDEO": "Video", "MULTI_CHANNEL": "Universal app", "EXPRESS": "Express",
+		},
+	},
+	"ApprovalStatus": {
+		display: map[string]string{
+			"APPROVED": "approved", "PENDING_REVIEW": "pending review", "UNDER_REVIEW": "under review",
+			"DISAPPROVED": "disapproved",
+		},
+	},
+	"BudgetStatus": {
+		renamed: map[string]string{"UNSPECIFIED": "UNKNOWN", "UNKNOWN": "UNKNOWN"},
+		display: map[string]string{"ENABLED": "enabled", "REMOVED": "removed", "UNKNOWN": "unknown"},
+	},
+	"CampaignStatus": {
+		renamed: map[string]string{"UNSPECIFIED": "UNKNOWN", "UNKNOWN": "UNKNOWN"},
+		display: map[string]string{"UNKNOWN": "unknown", "ENABLED": "enabled", "PAUSED": "paused", "REMOVED": "removed"},
+	},
+	"DayOfWeek": {
+		display: map[string]string{
+			"MONDAY": "Monday", "TUESDAY": "Tuesday", "WEDNESDAY": "Wednesday", "THURSDAY": "Thursday",
+			"FRIDAY": "Friday", "SATURDAY": "Saturday", "SUNDAY": "Sunday",
+		},
+	},
+	"DeviceType": {
+		renamed: map[string]string{
+			"UNSPECIFIED": "UNKNOWN", "UNKNOWN": "UNKNOWN", "OTHER": "UNKNOWN", "MOBILE": "HIGH_END_MOBILE",
+		},
+		display: map[string]string{
+			"UNKNOWN": "Other", "DESKTOP": "Computers", "HIGH_END_MOBILE": "Mobile devices with full browsers",
+			"TABLET": "Tablets with full browsers",
+		},
+	},
+	"Display Name": {
+		renamed: map[string]string{
+			"UNSPECIFIED": "UNKNOWN", "UNKNOWN": "UNKNOWN", "SHOPPING_PRODUCT_AD": "PRODUCT_AD",
+			"SHOPPING_SMART_AD": "GOAL_OPTIMIZED_SHOPPING_AD", "LEGACY_RESPONSIVE_DISPLAY_AD": "RESPONSIVE_DISPLAY_AD",
+			"RESPONSIVE_DISPLAY_AD": "MULTI_ASSET_RESPONSIVE_DISPLAY_AD",
+		},
+		display: map[string]string{
+			"DEPRECATED_AD": "Deprecated ad", "IMAGE_AD": "Image ad", "PRODUCT_AD": "Product ad",
+			"TEMPLATE_AD": "Template ad", "TEXT_AD": "Text ad", "THIRD_PARTY_REDIRECT_AD": "Third party ad",
+			"DYNAMIC_SEARCH_AD": "Dynamic search ad", "CALL_ONLY_AD": "Call-only ad",
+			"EXPANDED_TEXT_AD": "Expanded text ad", "RESPONSIVE_DISPLAY_AD": "Responsive display ad",
+			"SHOWCASE_AD": "Showcase ad", "GOAL_OPTIMIZED_SHOPPING_AD": "Smart Shopping ad",
+			"EXPANDED_DYNAMIC_SEARCH_AD": "Expanded dynamic search ad", "GMAIL_AD": "Gmail ad",
+			"RESPONSIVE_SEARCH_AD": "Responsive search ad", "UNKNOWN": "unknown",
+			"MULTI_ASSET_RESPONSIVE_DISPLAY_AD": "Multi-asset responsive display ad",
+		},
+	},
+	"GeoTargetType": {
+		renamed: map[string]string{"UNSPECIFIED": "UNKNOWN", "UNKNOWN": "UNKNOWN"},
+		display: map[string]string{
+			"AREA_OF_INTEREST": "Area of interest", "LOCATION_OF_PRESENCE": "Location of presence", "UNKNOWN": "unknown",
+		},
+	},
+	"KeywordMatchType": {
+		display: map[string]string{"EXACT": "Exact", "PHRASE": "Phrase", "BROAD": "Broad"},
+	},
+	"MonthOfYear": {
+		display: map[string]string{
+			"JANUARY": "January", "FEBRUARY": "February", "MARCH": "March", "APRIL": "April", "MAY": "May",
+			"JUNE": "June", "JULY": "July", "AUGUST": "August", "SEPTEMBER": "September", "OCTOBER": "October",
+			"NOVEMBER": "November", "DECEMBER": "December",
+		},
+	},
+	"PolicyApprovalStatus": {
+		renamed: map[string]string{
+			"UNSPECIFIED": "UNKNOWN", "UNKNOWN": "UNKNOWN", "APPROVED_LIMITED": "APPROVED_LIMITED",
+			"AREA_OF_INTEREST_ONLY": "APPROVED_LIMITED",
+		},
+		display: map[string]string{
+			"UNKNOWN": "unknown", "APPROVED": "approved", "APPROVED_LIMITED": "approved (limited)",
+			"ELIGIBLE": "eligible", "UNDER_REVIEW": "under review", "DISAPPROVED": "disapproved",
+			"SITE_SUSPENDED": "site suspended",
+		},
+	},
+	"QueryMatchTypeWithVariant": {
+		display: map[string]string{
+			"AUTO": "auto", "BROAD": "broad", "EXACT": "exact", "EXPANDED": "expanded", "PHRASE": "phrase",
+			"NEAR_EXACT": "exact (close variant)", "NEAR_PHRASE": "phrase (close variant)",
+		},
+	},
+	"QueryTargetingStatus": {
+		renamed: map[string]string{"ADDED_EXCLUDED": "BOTH"},
+		display: map[string]string{"ADDED": "Added", "EXCLUDED": "Excluded", "BOTH": "Both", "NONE": "None"},
+	},
+	"ServingStatus": {
+		display: map[string]string{
+			"SERVING": "eligible", "NONE": "none", "ENDED": "ended", "PENDING": "pending", "SUSPENDED": "suspended",
+		},
+	},
+	"Slot": {
+		renamed: map[string]string{
+			"UNSPECIFIED": "Unknown", "UNKNOWN": "Unknown", "SEARCH_SIDE": "SearchRhs", "SEARCH_TOP": "SearchTop",
+			"SEARCH_OTHER": "SearchOther", "CONTENT": "Content", "SEARCH_PARTNER_TOP": "AfsTop",
+			"SEARCH_PARTNER_OTHER": "AfsOther", "MIXED": "Mixed",
+		},
+		display: map[string]string{
+			"SearchRhs": "Google search: Side", "SearchTop": "Google search: Top", "SearchOther": "Google search: Other",
+			"Content": "Google Display Network", "AfsTop": "Search partners: Top", "AfsOther": "Search partners: Other",
+			"Mixed": "Cross-network", "Unknown": "Unknown",
+		},
+	},
+	"Status": {
+		renamed: map[string]string{"REMOVED": "DISABLED"},
+		display: map[string]string{"ENABLED": "enabled", "PAUSED": "paused", "DISABLED": "disabled"},
+	},
+	"SystemServingStatus": {
+		display: map[string]string{"ELIGIBLE": "eligible", "RARELY_SERVED": "rarely served"},
+	},
+	"UserStatus": {
+		display: map[string]string{"ENABLED": "enabled", "REMOVED": "removed", "PAUSED": "paused"},
+	},
+}
+
+// gaqlBoolKind is the kind of the legacy columns with true or false as values, as in the Google Ads API.
+const gaqlBoolKind = "Enum"
+
+// gaqlColumn represents a legacy column requested to the Google Ads API with its field.
+// With an enum kind, its values are these of the legacy column in the schema.
+type gaqlColumn struct {
+	name, field string
+	enum        *gaqlEnum
+	values      []string
+}
+
+// gaqlOperators maps the operators of AWQL to these of GAQL, when they differ.
+var gaqlOperators = map[string]string{
+	"NOT_IN":        "NOT IN",
+	"CONTAINS_ALL":  "CONTAINS ALL",
+	"CONTAINS_ANY":  "CONTAINS ANY",
+	"CONTAINS_NONE": "CONTAINS NONE",
+}
+
+// gaqlColumnOf returns the column of the legacy report with its field in the Google Ads API.
+// The columns without field, unknown in the report or of an enum kind without values in the API are not supported.
+func gaqlColumnOf(r gaqlResource, t db.DataTable, name string) (gaqlColumn, error) {
+	c := gaqlColumn{name: name}
+	var ok bool
+	if c.field, ok = r.fields[name]; !ok {
+		if c.field, ok = gaqlFields[name]; !ok {
+			return c, NewXError("column not supported by google ads", name)
+		}
+	}
+	f, err := t.Field(name)
+	if err != nil {
+		return c, NewXError("column not supported by google ads", name)
+	}
+	if c.values = f.ValueList(); len(c.values) == 0 || f.Kind() == gaqlBoolKind {
+		return c, nil
+	}
+	e, ok := gaqlEnums[f.Kind()]
+	if !ok {
+		return c, NewXError("column not supported by google ads", name)
+	}
+	c.enum = &e
+
+	return c, nil
+}
+
+// gaqlQuery translates the statement on the legacy report in a query of the Google Ads Query Language.
+// It returns the query with the columns requested, in the order of the legacy columns of the statement.
+// The legacy values of the enum columns are translated in these of the API.
+// Without the support of zero impressions, the rows without impression are excluded as in the reports.
+func gaqlQuery(stmt *parser.SelectStatement, t db.DataTable, zero bool) (string, []gaqlColumn, error) {
+	r, ok := gaqlResources[stmt.SourceName()]
+	if !ok {
+		return "", nil, NewXError("report not supported by google ads", stmt.SourceName())
+	}
+	var (
+		columns  []gaqlColumn
+		selected []string
+		metrics  bool
+	)
+	seen := make(map[string]bool)
+	for _, name := range stmt.LegacyColumns() {
+		c, err := gaqlColumnOf(r, t, name)
+		if err != nil {
+			return "", nil, err
+		}
+		columns = append(columns, c)
+		if !seen[c.field] {
+			seen[c.field] = true
+			selected = append(selected, c.field)
+		}
+		metrics = metrics || strings.HasPrefix(c.field, "metrics.")
+	}
+	q := "SELECT " + strings.Join(selected, ", ") + " FROM " + r.name
+
+	// quote returns the value as string literal of GAQL.
+	var quote = func(s string) string {
+		return "'" + strings.Replace(strings.Replace(s, `\`, `\\`, -1), "'", `\'`, -1) + "'"
+	}
+	// like escapes the wildcards of the LIKE operator.
+	var like = func(s string) string {
+		return strings.NewReplacer("[", "[[]", "%", "[%]", "_", "[_]").Replace(s)
+	}
+	var where []string
+	for _, cond := range stmt.ConditionList() {
+		c, err := gaqlColumnOf(r, t, cond.Name())
+		if err != nil {
+			return "", nil, err
+		}
+		f := c.field
+		val, lit := cond.Value()
+		if len(val) == 0 {
+			return "", nil, NewXError("invalid condition", cond.Name())
+		}
+		op := cond.Operator()
+		if c.enum != nil {
+			// Each legacy value becomes the values of the API matching it.
+			var list []string
+			for _, v := range val {
+				list = append(list, c.enum.values(v)...)
+			}
+			if len(list) > 1 {
+				switch op {
+				case opEqual:
+					op = opIn
+				case opDifferent:
+					op = opNotIn
+				}
+			}
+			val = list
+		}
+		var expr string
+		switch op {
+		case "STARTS_WITH":
+			expr = f + " LIKE " + quote(like(val[0])+"%")
+		case "CONTAINS":
+			expr = f + " LIKE " + quote("%"+like(val[0])+"%")
+		case "DOES_NOT_CONTAIN":
+			expr = f + " NOT LIKE " + quote("%"+like(val[0])+"%")
+		case "STARTS_WITH_IGNORE_CASE":
+			expr = f + " REGEXP_MATCH " + quote("(?i)"+regexp.QuoteMeta(val[0])+".*")
+		case "CONTAINS_IGNORE_CASE":
+			expr = f + " REGEXP_MATCH " + quote("(?i).*"+regexp.QuoteMeta(val[0])+".*")
+		case "DOES_NOT_CONTAIN_IGNORE_CASE":
+			expr = f + " NOT REGEXP_MATCH " + quote("(?i).*"+regexp.QuoteMeta(val[0])+".*")
+		default:
+			list := make([]string, len(val))
+			for i, v := range val {
+				if lit {
+					list[i] = v
+				} else {
+					list[i] = quote(v)
+				}
+			}
+			if strings.HasPrefix(op, "CONTAINS") || strings.HasSuffix(op, "IN") {
+				expr = f + " " + gaqlOperator(op) + " (" + strings.Join(list, ", ") + ")"
+			} else {
+				expr = f + " " + gaqlOperator(op) + " " + list[0]
+			}
+		}
+		where = append(where, expr)
+	}
+	if d := stmt.DuringList(); len(d) > 0 {
+		if len(d) == 1 {
+			d = duringDates(d[0])
+		}
+		var dates [2]string
+		for i := range dates {
+			t, err := time.Parse(dateFormat, d[i])
+			if err != nil {
+				return "", nil, NewXError("invalid date range", strings.Join(d, ","))
+			}
+			dates[i] = t.Format(gaqlDateFormat)
+		}
+		where = append(where, "segments.date BETWEEN '"+dates[0]+"' AND '"+dates[1]+"'")
+	}
+	if !zero && metrics {
+		where = append(where, "metrics.impressions > 0")
+	}
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	return q, columns, nil
+}
+
+// gaqlOperator returns the operator of GAQL for this one of AWQL.
+func gaqlOperator(op string) string {
+	if o, ok := gaqlOperators[op]; ok {
+		return o
+	}
+	return op
+}
+
+// gaqlFieldNames returns the fields of the Google Ads API of the columns.
+func gaqlFieldNames(columns []gaqlColumn) []string {
+	fields := make([]string, len(columns))
+	for i, c := range columns {
+		fields[i] = c.field
+	}
+	return fields
+}
+
+// gaqlRecords converts the values of the Google Ads API as outputted by the legacy reports:
+// the rates become percentages, the amounts in micros integers,
+// the resource names of the geographic targets their criterion ID
+// and the enum values their legacy value, displayed as in the reports without raw enum values.
+// The enum values without legacy value are kept as they are and returned as warnings.
+func gaqlRecords(records [][]string, columns []gaqlColumn, rawEnums bool) ([][]string, []error) {
+	var (
+		warns []error
+		seen  = make(map[string]bool)
+	)
+	for _, r := range records {
+		for i, c := range columns {
+			v := r[i]
+			if v == awql.NullValue {
+				continue
+			}
+			switch {
+			case c.enum != nil:
+				l := c.enum.legacy(v)
+				if !containsValue(c.values, l) {
+					if k := c.name + " = " + v; !seen[k] {
+						seen[k] = true
+						warns = append(warns, NewXError("value not supported by the report", k))
+					}
+					continue
+				}
+				if d, ok := c.enum.display[l]; ok && !rawEnums {
+					l = d
+				}
+				r[i] = l
+			case gaqlRates[c.field]:
+				if n, err := strconv.ParseFloat(v, 64); err == nil {
+					r[i] = strconv.FormatFloat(n*100, 'f', 2, 64) + "%"
+				}
+			case gaqlMicros[c.field]:
+				if n, err := strconv.ParseFloat(v, 64); err == nil {
+					r[i] = strconv.FormatFloat(math.Floor(n+0.5), 'f', 0, 64)
+				}
+			case strings.HasPrefix(c.field, "segments.geo_target_"):
+				r[i] = v[strings.LastIndex(v, "/")+1:]
+			}
+		}
+	}
+	return records, warns
+}
@@ -0,0 +1,197 @@
+package driver
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	db "github.com/rvflash/awql-db"
+	parser "github.com/rvflash/awql-parser"
+)
+
+// openSchema returns the database of the version, in a temporary directory.
+func openSchema(t *testing.T, version string) (*db.Database, func()) {
+	dir, err := ioutil.TempDir("", "awql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := db.OpenConfig(db.Config{Version: version, Dir: dir})
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("Expected no error, received %s", err)
+	}
+	return d, func() { os.RemoveAll(dir) }
+}
+
+// TestGaqlResources tests the mapping of the legacy reports against their schema.
+// Each column mapped exists in its report and each value of its enum kind has a display value.
+func TestGaqlResources(t *testing.T) {
+	d, done := openSchema(t, "v201809")
+	defer done()
+
+	for name, r := range gaqlResources {
+		tb, err := d.Table(name)
+		if err != nil {
+			t.Errorf("%s: expected no error, received %s", name, err)
+			continue
+		}
+		for col := range r.fields {
+			if _, err := tb.Field(col); err != nil {
+				t.Errorf("%s: expected %s in the report, received %s", name, col, err)
+			}
+		}
+		for _, c := range tb.Columns() {
+			f, ok := c.(db.Field)
+			if !ok {
+				t.Fatalf("%s: expected a field, received %T", name, c)
+			}
+			gc, err := gaqlColumnOf(r, tb, f.Name())
+			if err != nil || gc.enum == nil {
+				continue
+			}
+			for _, v := range f.ValueList() {
+				if _, ok := gc.enum.display[v]; !ok {
+					t.Errorf("%s: expected a display value for %s = %s", name, f.Name(), v)
+				}
+			}
+			for g, v := range gc.enum.renamed {
+				if !containsValue(f.ValueList(), v) {
+					t.Errorf("%s: expected %s = %s renamed in a value of the report, received %s", name, f.Name(), g, v)
+				}
+			}
+		}
+	}
+}
+
+// TestGaqlQuery tests the function named gaqlQuery.
+func TestGaqlQuery(t *testing.T) {
+	d, done := openSchema(t, "v201809")
+	defer done()
+
+	var queryTests = []struct {
+		q, gaql string
+		fields  []string
+		zero    bool
+		err     bool
+	}{
+		// Reports and columns without resource or field.
+		{q: "SELECT CriteriaId FROM AGE_RANGE_PERFORMANCE_REPORT", err: true},
+		{q: "SELECT ClickType, Clicks FROM CAMPAIGN_PERFORMANCE_REPORT", err: true},
+		{q: "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT WHERE ClickType = 'URL_CLICKS'", err: true},
+		{q: "SELECT Oops FROM CAMPAIGN_PERFORMANCE_REPORT", err: true},
+		{
+			q:      "SELECT CampaignName, Clicks, Cost FROM CAMPAIGN_PERFORMANCE_REPORT DURING 20180101,20180131",
+			gaql:   "SELECT campaign.name, metrics.clicks, metrics.cost_micros FROM campaign WHERE segments.date BETWEEN '2018-01-01' AND '2018-01-31' AND metrics.impressions > 0",
+			fields: []string{"campaign.name", "metrics.clicks", "metrics.cost_micros"},
+		},
+		{
+			q:      "SELECT CampaignName, CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignName STARTS_WITH 'a_b'",
+			gaql:   "SELECT campaign.name FROM campaign WHERE campaign.name LIKE 'a[_]b%'",
+			fields: []string{"campaign.name"},
+		},
+		{
+			q:      "SELECT Clicks FROM CAMPAIGN_PERFORMANCE_REPORT",
+			gaql:   "SELECT metrics.clicks FROM campaign",
+			fields: []string{"metrics.clicks"},
+			zero:   true,
+		},
+		// Enum values.
+		{
+			q:      "SELECT CampaignStatus FROM CAMPAIGN_PERFORMANCE_REPORT WHERE CampaignStatus IN ['ENABLED', 'PAUSED']",
+			gaql:   "SELECT campaign.status FROM campaign WHERE campaign.status IN ('ENABLED', 'PAUSED')",
+			fields: []string{"campaign.status"},
+		},
+		{
+			q:      "SELECT Device, Clicks FROM CAMPAIGN_PERFORMANCE_REPORT WHERE Device = 'HIGH_END_MOBILE'",
+			gaql:   "SELECT segments.device, metrics.clicks FROM campaign WHERE segments.device = 'MOBILE' AND metrics.impressions > 0",
+			fields: []string{"segments.device", "metrics.clicks"},
+		},
+		{
+			q:      "SELECT Clicks FROM CAMPAIGN_PERFORMANCE_REPORT WHERE AdNetworkType1 = 'SEARCH' AND Device != 'UNKNOWN'",
+			gaql:   "SELECT metrics.clicks FROM campaign WHERE segments.ad_network_type IN ('SEARCH', 'SEARCH_PARTNERS') AND segments.device NOT IN ('OTHER', 'UNKNOWN', 'UNSPECIFIED') AND metrics.impressions > 0",
+			fields: []string{"metrics.clicks"},
+		},
+		{
+			q:      "SELECT Slot FROM CAMPAIGN_PERFORMANCE_REPORT WHERE Slot = 'SearchTop'",
+			gaql:   "SELECT segments.slot FROM campaign WHERE segments.slot = 'SEARCH_TOP'",
+			fields: []string{"segments.slot"},
+		},
+	}
+	for i, tt := range queryTests {
+		stmt, err := parser.NewParser(strings.NewReader(tt.q)).ParseSelect()
+		if err != nil {
+			t.Fatalf("%d. Expected no error, received %s", i, err)
+		}
+		ss := stmt.(*parser.SelectStatement)
+		tb, err := d.Table(ss.SourceName())
+		if err != nil {
+			// The reports without resource are also rejected without table.
+			tb = db.Table{}
+		}
+		q, columns, err := gaqlQuery(ss, tb, tt.zero)
+		if tt.err != (err != nil) {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if q != tt.gaql {
+			t.Errorf("%d. Expected %q, received %q", i, tt.gaql, q)
+		} else if fields := gaqlFieldNames(columns); !tt.err && !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("%d. Expected %v, received %v", i, tt.fields, fields)
+		}
+	}
+}
+
+// TestGaqlRecords tests the function named gaqlRecords.
+func TestGaqlRecords(t *testing.T) {
+	var (
+		status = gaqlEnums["CampaignStatus"]
+		device = gaqlEnums["DeviceType"]
+		slot   = gaqlEnums["Slot"]
+	)
+	columns := []gaqlColumn{
+		{name: "CampaignStatus", field: "campaign.status", enum: &status, values: []string{"UNKNOWN", "ENABLED", "PAUSED", "REMOVED"}},
+		{name: "Device", field: "segments.device", enum: &device, values: []string{"UNKNOWN", "DESKTOP", "HIGH_END_MOBILE", "TABLET"}},
+		{name: "Slot", field: "segments.slot", enum: &slot, values: []string{"SearchTop", "SearchOther", "Unknown"}},
+		{name: "Ctr", field: "metrics.ctr"},
+		{name: "AverageCpc", field: "metrics.average_cpc"},
+		{name: "CityCriteriaId", field: "segments.geo_target_city"},
+	}
+	// Returns the records of the Google Ads API.
+	var records = func() [][]string {
+		return [][]string{
+			{"ENABLED", "MOBILE", "SEARCH_TOP", "0.1234", "1234567.6", "geoTargetConstants/1006094"},
+			{"PAUSED", "CONNECTED_TV", "UNSPECIFIED", "0", "0", " -- "},
+			{"ENABLED", "CONNECTED_TV", "SEARCH_OTHER", " -- ", " -- ", " -- "},
+		}
+	}
+	var recordTests = []struct {
+		rawEnums bool
+		out      [][]string
+	}{
+		{
+			rawEnums: true,
+			out: [][]string{
+				{"ENABLED", "HIGH_END_MOBILE", "SearchTop", "12.34%", "1234568", "1006094"},
+				{"PAUSED", "CONNECTED_TV", "Unknown", "0.00%", "0", " -- "},
+				{"ENABLED", "CONNECTED_TV", "SearchOther", " -- ", " -- ", " -- "},
+			},
+		},
+		{
+			out: [][]string{
+				{"enabled", "Mobile devices with full browsers", "Google search: Top", "12.34%", "1234568", "1006094"},
+				{"paused", "CONNECTED_TV", "Unknown", "0.00%", "0", " -- "},
+				{"enabled", "CONNECTED_TV", "Google search: Other", " -- ", " -- ", " -- "},
+			},
+		},
+	}
+	for i, tt := range recordTests {
+		out, warns := gaqlRecords(records(), columns, tt.rawEnums)
+		if !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("%d. Expected %q, received %q", i, tt.out, out)
+		}
+		// The value without legacy value is only reported once.
+		if len(warns) != 1 || !strings.Contains(warns[0].Error(), "Device = CONNECTED_TV") {
+			t.Errorf("%d. Expected one warning about CONNECTED_TV, received %v", i, warns)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	db "github.com/rvflash/awql-db"
+	awql "github.com/rvflash/awql-driver"
+	parser "github.com/rvflash/awql-parser"
+	cache "github.com/rvflash/csv-cache"
+)
+
+// snapshotDir is the directory, inside the cache one, where the snapshots of the materialized views are stored.
+const snapshotDir = "views"
+
+// snapshotTTL is the lifetime of a snapshot in its storage.
+// It never expires, its refresh is driven by the interval of its materialized view.
+const snapshotTTL = time.Duration(math.MaxInt64)
+
+// refreshLayout is the format of the last refresh time of a materialized view.
+const refreshLayout = "2006-01-02 15:04:05"
+
+// snapshotKey returns the key of the snapshot of the materialized view for this Adwords ID.
+// The definition of the view is a part of it, a view altered never uses the snapshot of its previous version.
+func (s *Stmt) snapshotKey(name string) (string, error) {
+	vs, err := s.db.ViewStmt(name)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := vs.Materialized(); !ok {
+		return "", NewXError("not a materialized view", name)
+	}
+	return "view-" + s.id + "-" + vs.String(), nil
+}
+
+// lastRefresh returns the last time the snapshot of the view has been refreshed.
+// The time is zero if the view is not materialized or never refreshed.
+func (s *Stmt) lastRefresh(t db.DataTable) Time {
+	if _, ok := t.Materialized(); !ok {
+		return Time{Layout: refreshLayout}
+	}
+	key, err := s.snapshotKey(t.SourceName())
+	if err != nil {
+		return Time{Layout: refreshLayout}
+	}
+	mt, err := s.sc.ModTime(key)
+	if err != nil {
+		return Time{Layout: refreshLayout}
+	}
+	return Time{Time: mt, Layout: refreshLayout}
+}
+
+// dropSnapshot removes the snapshot of the materialized view, if exists.
+func (s *Stmt) dropSnapshot(key string) {
+	if key != "" {
+		s.sc.Delete(key)
+	}
+}
+
+// isOutdated returns true if the refresh interval of the view is elapsed since the last refresh of its snapshot.
+func (s *Stmt) isOutdated(t db.DataTable, key string) bool {
+	refresh, _ := t.Materialized()
+	if refresh == 0 {
+		// Only refreshed on demand.
+		return false
+	}
+	mt, err := s.sc.ModTime(key)
+	return err != nil || time.Now().After(mt.Add(refresh))
+}
+
+// snapshot returns the records of the materialized view, refreshed if they are missing or outdated.
+// They are filtered by the conditions of the statement and ordered as the columns of its legacy query.
+func (s *Stmt) snapshot(stmt *parser.SelectStatement, t db.DataTable, key string) ([][]string, error) {
+	records, err := s.sc.Get(key)
+	if err != nil || len(records) == 0 || s.isOutdated(t, key) {
+		if records, err = s.refreshView(t, key); err != nil && err != ErrSnapshot {
+			return nil, err
+		}
+	}
+
+	return filterRecords(stmt, records)
+}
+
+// filterRecords returns the records matching the conditions of the statement,
+// with their values ordered as the columns of its legacy query.
+// The first record is the list of the column names, it is not returned.
+func filterRecords(stmt *parser.SelectStatement, records [][]string) ([][]string, error) {
+	// Positions of the columns in the records, given by the first one.
+	index := make(map[string]int)
+	for p, name := range records[0] {
+		index[name] = p
+	}
+	cols := stmt.LegacyColumns()
+	pos := make([]int, len(cols))
+	for i, name := range cols {
+		p, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("%s (%v)", db.ErrUnknownColumn, name)
+		}
+		pos[i] = p
+	}
+	for _, c := range stmt.Where {
+		if _, ok := index[c.Name()]; !ok {
+			return nil, fmt.Errorf("%s (%v)", db.ErrUnknownColumn, c.Name())
+		}
+	}
+
+	// Filters the records and re-orders their values.
+	var data [][]string
+	for _, r := range records[1:] {
+		ok := true
+		for _, c := range stmt.Where {
+			if ok = matchCondition(r[index[c.Name()]], c); !ok {
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		v := make([]string, len(pos))
+		for i, p := range pos {
+			v[i] = r[p]
+		}
+		data = append(data, v)
+	}
+	return data, nil
+}
+
+// refreshView requests the data of the materialized view and stores them as its snapshot.
+// The first record is the list of the column names.
+// ErrSnapshot is returned with the records if they can not be stored.
+func (s *Stmt) refreshView(t db.DataTable, key string) ([][]string, error) {
+	// Requests the Adwords API, without cache or snapshot.
+	q := "SELECT * FROM " + t.SourceName()
+	vs := &Stmt{
+		si:    &awql.Stmt{Db: s.si.Db, SrcQuery: q},
+		query: q,
+		db:    s.db,
+		fc:    cache.New("", 0),
+		ss:    s.ss,
+		mu:    s.mu,
+		opts:  s.opts,
+		id:    s.id,
+		ads:   s.ads,
+	}
+	if err := vs.BindNamed(nil); err != nil {
+		return nil, err
+	}
+	rows, err := NewSelectStmt(vs).Query()
+	if err != nil {
+		return nil, err
+	}
+	rs := rows.(*Rows)
+
+	// Saves the values as strings, as Google outputs them, under the column names of the view.
+	cols := t.Columns()
+	if len(rs.cols) != len(cols) {
+		return nil, NewXError("invalid materialized view", t.SourceName())
+	}
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Name()
+	}
+	records := [][]string{header}
+	for {
+		dest := make([]driver.Value, len(rs.cols))
+		if err := rs.Next(dest); err != nil {
+			break
+		}
+		r := make([]string, len(dest))
+		for i, v := range dest {
+			r[i] = fmt.Sprint(v)
+		}
+		records = append(records, r)
+	}
+	if err := s.sc.Set(&cache.Item{Key: key, Value: records}); err != nil {
+		return records, ErrSnapshot
+	}
+	return records, nil
+}
+
+// snapshotTable returns the table to use to query the snapshot of the materialized view.
+// Its columns are these of the view, already computed and aggregated:
+// the expressions and the aggregate functions are removed, the counters become numeric columns.
+func snapshotTable(t db.DataTable) db.Table {
+	v := t.(db.Table)
+	snap := db.Table{Name: v.Name, PrimaryKey: v.PrimaryKey, View: db.View{Name: v.View.Name}}
+	snap.Cols = make([]db.Column, len(v.Cols))
+	for i, c := range v.Cols {
+		if strings.ToUpper(c.Method) == "COUNT" {
+			c.Type = "Long"
+		}
+		c.Expr, c.Method, c.Unique = "", "", false
+		snap.Cols[i] = c
+	}
+	if _, err := snap.Field(snap.PrimaryKey); err != nil && len(snap.Cols) > 0 {
+		// The rows are counted on the first column.
+		snap.PrimaryKey = snap.Cols[0].Head
+	}
+	return snap
+}
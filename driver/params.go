@@ -0,0 +1,71 @@
+package driver
+
+import (
+	"strconv"
+	"strings"
+
+	db "github.com/rvflash/awql-db"
+	parser "github.com/rvflash/awql-parser"
+)
+
+// viewConditions returns the conditions of the view, with each parameter replaced by the value of its argument.
+// The data source of the view is used to check the values against the kind of the filtered columns.
+// It returns an error if an argument is unknown, missing or invalid.
+func viewConditions(view, src db.DataTable, args []parser.Argument) ([]parser.Condition, error) {
+	// Indexes the arguments by parameter.
+	values := make(map[string]parser.Argument)
+	for _, a := range args {
+		if _, ok := values[a.Name()]; ok || !containsValue(view.Parameters(), a.Name()) {
+			return nil, NewXError("invalid parameter", a.Name())
+		}
+		values[a.Name()] = a
+	}
+	for _, name := range view.Parameters() {
+		if _, ok := values[name]; !ok {
+			return nil, NewXError("missing parameter", name)
+		}
+	}
+	conds := view.SourceQuery().ConditionList()
+	if len(values) == 0 {
+		return conds, nil
+	}
+	where := make([]parser.Condition, len(conds))
+	for i, c := range conds {
+		name, ok := parser.ParameterOf(c)
+		if !ok {
+			where[i] = c
+			continue
+		}
+		f, err := src.Field(c.Name())
+		if err != nil {
+			return nil, NewXError("invalid parameter", name)
+		}
+		v, _ := values[name].Value()
+		literal, err := checkArgument(f, v)
+		if err != nil {
+			return nil, NewXError("invalid parameter", name)
+		}
+		where[i] = newCondition(c.Name(), c.Operator(), []string{v}, literal)
+	}
+	return where, nil
+}
+
+// checkArgument checks the value against the kind of the column or its list of values.
+// It returns true if the value must be used as value literal, false if it is a string.
+func checkArgument(f db.Field, v string) (literal bool, err error) {
+	switch strings.ToUpper(f.Kind()) {
+	case "BID", "INT", "INTEGER", "LONG", "MONEY":
+		_, err = strconv.ParseInt(v, 10, 64)
+		return true, err
+	case "DOUBLE":
+		_, err = strconv.ParseFloat(v, 64)
+		return true, err
+	case "BOOLEAN":
+		_, err = strconv.ParseBool(v)
+		return true, err
+	}
+	if list := f.ValueList(); len(list) > 0 && !containsValue(list, v) {
+		return false, ErrParameter
+	}
+	return false, nil
+}
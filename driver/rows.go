@@ -3,16 +3,25 @@ package driver
 import (
 	"database/sql/driver"
 	"io"
+	"reflect"
 	"sort"
+	"strings"
+
+	db "github.com/rvflash/awql-db"
 )
 
 // Rows is an iterator over an executed query's results.
 // It implements sort and driver.Rows interfaces.
+// It also implements the driver.RowsColumnTypeScanType, driver.RowsColumnTypeDatabaseTypeName,
+// driver.RowsColumnTypeNullable and driver.RowsNextResultSet interfaces.
 type Rows struct {
 	data      [][]driver.Value
 	less      []lessFunc
 	cols      []string
+	fields    []db.Field
 	size, pos int
+	typed     bool
+	next      *Rows
 }
 
 // Len
@@ -49,14 +58,39 @@ func (r *Rows) Swap(i, j int) {
 	r.data[i], r.data[j] = r.data[j], r.data[i]
 }
 
-// Columns returns the names of the columns.
+// Columns returns the names of the columns, even without rows.
 func (r *Rows) Columns() []string {
-	if r.size == 0 {
-		return nil
-	}
 	return r.cols
 }
 
+// ColumnTypeDatabaseTypeName returns the database system type name of the column.
+// It's the upper case version of the Adwords kind, like MONEY or CAMPAIGNSTATUS.
+func (r *Rows) ColumnTypeDatabaseTypeName(index int) string {
+	if f := r.field(index); f != nil {
+		return strings.ToUpper(f.Kind())
+	}
+	return "STRING"
+}
+
+// ColumnTypeNullable returns true if the column may be null.
+// With typed values, all Adwords fields are nullable, they can be not set or excluded.
+func (r *Rows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if !r.typed {
+		return false, true
+	}
+	return r.field(index) != nil, true
+}
+
+// ColumnTypeScanType returns the value type that can be used to scan types into.
+func (r *Rows) ColumnTypeScanType(index int) reflect.Type {
+	f := r.field(index)
+	if !r.typed || f == nil {
+		return reflect.TypeOf("")
+	}
+	method, _ := f.UseFunction()
+	return scanType(f.Kind(), method)
+}
+
 // Close closes the rows iterator.
 func (r *Rows) Close() error {
 	return nil
@@ -68,7 +102,16 @@ func (r *Rows) Next(dest []driver.Value) error {
 		return io.EOF
 	}
 	for i := 0; i < len(r.cols); i++ {
-		// todo Improves with Scanner interface.
+		if r.typed {
+			// Returns the value with its Go type.
+			if v, ok := r.data[r.pos][i].(NativeValuer); ok {
+				dest[i] = v.NativeValue()
+			} else {
+				dest[i] = r.data[r.pos][i]
+			}
+			continue
+		}
+		// By default, returns the value as a string as Google outputs it.
 		switch r.data[r.pos][i].(type) {
 		case AutoExcludedNullInt64:
 			dest[i], _ = r.data[r.pos][i].(AutoExcludedNullInt64).Value()
@@ -89,6 +132,22 @@ func (r *Rows) Next(dest []driver.Value) error {
 	return nil
 }
 
+// HasNextResultSet returns true if a result set follows this one, as the report summary.
+// It implements the driver.RowsNextResultSet interface.
+func (r *Rows) HasNextResultSet() bool {
+	return r.next != nil
+}
+
+// NextResultSet advances to the next result set.
+func (r *Rows) NextResultSet() error {
+	if r.next == nil {
+		return io.EOF
+	}
+	*r = *r.next
+
+	return nil
+}
+
 // Limit bounds the slice of rows.
 func (r *Rows) Limit(offset, rowCount int) {
 	if offset < 0 {
@@ -103,6 +162,14 @@ func (r *Rows) Limit(offset, rowCount int) {
 	r.size = rowCount
 }
 
+// field returns the field properties of the column at this index, nil if unknown.
+func (r *Rows) field(index int) db.Field {
+	if index < 0 || index >= len(r.fields) {
+		return nil
+	}
+	return r.fields[index]
+}
+
 // Sort sorts rows as expected by less functions.
 func (r *Rows) Sort() {
 	sort.Sort(r)
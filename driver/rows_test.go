@@ -0,0 +1,58 @@
+package driver_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestRows_Columns tests that the columns of the result sets are returned, even without rows.
+func TestRows_Columns(t *testing.T) {
+	d, done := openDB(t)
+	defer done()
+
+	// The conditions of the view and of the statement exclude each other, Adwords is not requested.
+	if _, err := d.Exec("CREATE VIEW CLICKED AS SELECT CampaignName, Clicks FROM CAMPAIGN_PERFORMANCE_REPORT WHERE Clicks > 10"); err != nil {
+		t.Fatalf("Expected no error, received %s", err)
+	}
+
+	var columnTests = []struct {
+		q    string
+		cols []string
+		size int
+	}{
+		{
+			q:    "SHOW COLUMNS FROM CAMPAIGN_PERFORMANCE_REPORT WHERE Field = 'CampaignId'",
+			cols: []string{"Field", "Type", "Key", "Supports_Zero_Impressions"},
+			size: 1,
+		},
+		{
+			q:    "SHOW COLUMNS FROM CAMPAIGN_PERFORMANCE_REPORT WHERE Type = 'Oops'",
+			cols: []string{"Field", "Type", "Key", "Supports_Zero_Impressions"},
+		},
+		{
+			q:    "SELECT CampaignName FROM CLICKED WHERE Clicks < 5",
+			cols: []string{"CampaignName"},
+		},
+	}
+	for i, tt := range columnTests {
+		rs, err := d.Query(tt.q)
+		if err != nil {
+			t.Errorf("%d. Expected no error, received %s", i, err)
+			continue
+		}
+		cols, err := rs.Columns()
+		if err != nil {
+			t.Errorf("%d. Expected no error, received %s", i, err)
+		} else if !reflect.DeepEqual(cols, tt.cols) {
+			t.Errorf("%d. Expected %v, received %v", i, tt.cols, cols)
+		}
+		var size int
+		for rs.Next() {
+			size++
+		}
+		rs.Close()
+		if size != tt.size {
+			t.Errorf("%d. Expected %d rows, received %d", i, tt.size, size)
+		}
+	}
+}
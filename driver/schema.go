@@ -0,0 +1,157 @@
+package driver
+
+import (
+	"strconv"
+	"strings"
+
+	db "github.com/rvflash/awql-db"
+	parser "github.com/rvflash/awql-parser"
+)
+
+// Names of the tables of the information schema.
+const (
+	schemaTables             = parser.InformationSchema + ".TABLES"
+	schemaColumns            = parser.InformationSchema + ".COLUMNS"
+	schemaViews              = parser.InformationSchema + ".VIEWS"
+	schemaColumnIncompatible = parser.InformationSchema + ".COLUMN_INCOMPATIBILITIES"
+)
+
+// schemaBool is the list of values of a boolean column of the information schema.
+var schemaBool = []string{"NO", "YES"}
+
+// schemaTable returns the definition of the table of the information schema with this name.
+// The second parameter is false if it is not one of them.
+func schemaTable(name string) (db.Table, bool) {
+	// column returns a column of the table.
+	var column = func(name, kind string, enum ...string) db.Column {
+		return db.Column{Head: name, Type: kind, Enum: enum}
+	}
+	origins := []string{db.OriginAdwords, db.OriginCatalog, db.OriginUser}
+
+	t := db.Table{Name: name, PrimaryKey: "TABLE_NAME"}
+	switch name {
+	case schemaTables:
+		t.Cols = []db.Column{
+			column("TABLE_NAME", "String"),
+			column("TABLE_TYPE", "String", "BASE TABLE", "VIEW"),
+			column("TABLE_ORIGIN", "String", origins...),
+			column("AGGREGATE_COLUMN", "String"),
+			column("COLUMN_COUNT", "Long"),
+		}
+	case schemaColumns:
+		t.Cols = []db.Column{
+			column("TABLE_NAME", "String"),
+			column("COLUMN_NAME", "String"),
+			column("ORDINAL_POSITION", "Long"),
+			column("DATA_TYPE", "String"),
+			column("COLUMN_KEY", "String"),
+			column("IS_SEGMENT", "String", schemaBool...),
+			column("SUPPORTS_ZERO_IMPRESSIONS", "String", schemaBool...),
+			column("ENUM_VALUES", "String"),
+		}
+	case schemaViews:
+		t.Cols = []db.Column{
+			column("TABLE_NAME", "String"),
+			column("TABLE_ORIGIN", "String", origins...),
+			column("SOURCE_NAME", "String"),
+			column("VIEW_DEFINITION", "String"),
+			column("IS_MATERIALIZED", "String", schemaBool...),
+			column("PARAMETERS", "String"),
+		}
+	case schemaColumnIncompatible:
+		t.Cols = []db.Column{
+			column("TABLE_NAME", "String"),
+			column("COLUMN_NAME", "String"),
+			column("INCOMPATIBLE_COLUMN_NAME", "String"),
+		}
+	default:
+		return t, false
+	}
+	return t, true
+}
+
+// table returns the table or the view with this name, or the table of the information schema.
+func (s *Stmt) table(name string) (db.DataTable, error) {
+	if t, ok := schemaTable(name); ok {
+		return t, nil
+	}
+	return s.db.Table(name)
+}
+
+// schema returns the records of the table of the information schema, built with the tables of the database.
+// The first record is the list of the column names.
+func (s *Stmt) schema(t db.DataTable) [][]string {
+	// formatBool returns a string representation of a boolean.
+	var formatBool = func(ok bool) string {
+		if ok {
+			return schemaBool[1]
+		}
+		return schemaBool[0]
+	}
+	// columnName returns the name of the column, or its alias in a view.
+	var columnName = func(tb db.DataTable, f db.Field) string {
+		if tb.IsView() && f.Alias() != "" {
+			return f.Alias()
+		}
+		return f.Name()
+	}
+
+	cols := t.Columns()
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.Name()
+	}
+	records := [][]string{header}
+
+	tables, _ := s.db.Tables()
+	for _, tb := range tables {
+		switch t.SourceName() {
+		case schemaTables:
+			kind := "BASE TABLE"
+			if tb.IsView() {
+				kind = "VIEW"
+			}
+			records = append(records, []string{
+				tb.SourceName(), kind, tb.Origin(), tb.AggregateFieldName(), strconv.Itoa(len(tb.Columns())),
+			})
+		case schemaColumns:
+			for i, c := range tb.Columns() {
+				f := c.(db.Field)
+				var key string
+				switch {
+				case f.IsSegment():
+					key = "MUL"
+				case f.Name() == tb.AggregateFieldName():
+					key = "PRI"
+				}
+				records = append(records, []string{
+					tb.SourceName(), columnName(tb, f), strconv.Itoa(i + 1), f.Kind(), key,
+					formatBool(f.IsSegment()), formatBool(f.SupportsZeroImpressions()),
+					strings.Join(f.ValueList(), ", "),
+				})
+			}
+		case schemaViews:
+			if !tb.IsView() {
+				continue
+			}
+			// The definition is the select statement of the view.
+			var def string
+			if vs, err := s.db.ViewStmt(tb.SourceName()); err == nil {
+				def = vs.SourceQuery().String()
+			}
+			_, materialized := tb.Materialized()
+			records = append(records, []string{
+				tb.SourceName(), tb.Origin(), tb.SourceQuery().SourceName(), def,
+				formatBool(materialized), strings.Join(tb.Parameters(), ", "),
+			})
+		case schemaColumnIncompatible:
+			for _, c := range tb.Columns() {
+				f := c.(db.Field)
+				for _, name := range f.NotCompatibleColumns() {
+					records = append(records, []string{tb.SourceName(), columnName(tb, f), name})
+				}
+			}
+		}
+	}
+	return records
+}
@@ -0,0 +1,280 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"sort"
+	"strings"
+	"sync"
+
+	parser "github.com/rvflash/awql-parser"
+)
+
+// Names of the session variables.
+const (
+	VarAPIVersion      = "api_version"
+	VarCache           = "cache"
+	VarRawEnumValues   = "raw_enum_values"
+	VarIncludeSummary  = "include_summary"
+	VarZeroImpressions = "zero_impressions"
+)
+
+// Values of the switches.
+const (
+	switchOn  = "ON"
+	switchOff = "OFF"
+)
+
+// variable represents the value of a user variable.
+// Its value is used as value literal if it is a literal or a number, as string otherwise.
+type variable struct {
+	value   string
+	literal bool
+}
+
+// String returns the value of the variable as AWQL literal.
+func (v variable) String() string {
+	if v.literal || isDigits(v.value) {
+		return v.value
+	}
+	return quote(v.value)
+}
+
+// session represents the settings of the session, changed with the SET statement.
+// Each connection has its own session, starting with the options of the data source name.
+// The version of the API and the use of the cache are read-only: they are these of the connector.
+type session struct {
+	version string
+	cache,
+	rawEnums,
+	summary,
+	zero bool
+	vars map[string]variable
+	mu   sync.RWMutex
+}
+
+// newSession returns a session with the options of the configuration.
+func newSession(cfg *Config) *session {
+	return &session{
+		version:  cfg.APIVersion,
+		cache:    cfg.WithCache,
+		rawEnums: cfg.UseRawEnumValues,
+		summary:  cfg.IncludeReportSummary,
+		zero:     cfg.SupportsZeroImpressions,
+	}
+}
+
+// get returns the use of the raw enum values, of the report summary and of the zero impressions.
+func (s *session) get() (rawEnums, summary, zero bool) {
+	if s == nil {
+		return
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.rawEnums, s.summary, s.zero
+}
+
+// apiVersion returns the version of the API used by the session.
+func (s *session) apiVersion() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.version
+}
+
+// set changes the value of the variable.
+// The name of a user variable starts with @, it is created on its first use.
+func (s *session) set(name, value string, literal bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.HasPrefix(name, "@") {
+		if s.vars == nil {
+			s.vars = make(map[string]variable)
+		}
+		s.vars[name] = variable{value: value, literal: literal}
+		return nil
+	}
+	var v *bool
+	switch strings.ToLower(name) {
+	case VarAPIVersion, VarCache:
+		return NewXError("read only variable", name)
+	case VarRawEnumValues:
+		v = &s.rawEnums
+	case VarIncludeSummary:
+		v = &s.summary
+	case VarZeroImpressions:
+		v = &s.zero
+	default:
+		return NewXError("unknown system variable", name)
+	}
+	on, ok := ParseSwitch(value)
+	if !ok {
+		return NewXError("invalid value", name+" = "+value)
+	}
+	*v = on
+
+	return nil
+}
+
+// list returns the name and the value of each variable of the session, sorted by name.
+func (s *session) list() [][2]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vars := [][2]string{
+		{VarAPIVersion, s.version},
+		{VarCache, formatSwitch(s.cache)},
+		{VarIncludeSummary, formatSwitch(s.summary)},
+		{VarRawEnumValues, formatSwitch(s.rawEnums)},
+		{VarZeroImpressions, formatSwitch(s.zero)},
+	}
+	for name, v := range s.vars {
+		vars = append(vars, [2]string{name, v.value})
+	}
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i][0] < vars[j][0]
+	})
+	return vars
+}
+
+// expand replaces each user variable of the SELECT or EXPLAIN query by its value.
+// The parameters of the views, followed by the equal sign, and the unknown variables are kept.
+// User variables inside quoted strings are ignored.
+func (s *session) expand(q string) string {
+	if s == nil {
+		return q
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.vars) == 0 {
+		return q
+	}
+	tokens := tokenize(q)
+	// Returns the index of the next significant token, -1 if there is none.
+	var next = func(at int) int {
+		for i := at + 1; i < len(tokens); i++ {
+			if tokens[i].tk != parser.WHITE_SPACE {
+				return i
+			}
+		}
+		return -1
+	}
+	if i := next(-1); i < 0 || (tokens[i].tk != parser.SELECT && tokens[i].tk != parser.EXPLAIN) {
+		return q
+	}
+	var buf []string
+	for i, t := range tokens {
+		if t.tk == parser.PARAMETER {
+			v, ok := s.vars[t.lit]
+			if n := next(i); ok && (n < 0 || tokens[n].tk != parser.EQUAL) {
+				t.raw = v.String()
+			}
+		}
+		buf = append(buf, t.raw)
+	}
+	return strings.Join(buf, "")
+}
+
+// SetStmt represents a Set statement.
+type SetStmt struct {
+	*Stmt
+}
+
+// NewSetStmt returns an instance of SetStmt.
+// It implements Execer interface.
+func NewSetStmt(stmt *Stmt) Execer {
+	return &SetStmt{stmt}
+}
+
+// Exec executes a Set query.
+// The new value of the variable is used by the next statements of this connection.
+func (s *SetStmt) Exec() (driver.Result, error) {
+	// Casts statement.
+	stmt := s.p.(parser.SetStmt)
+
+	if s.ss == nil {
+		return nil, NewXError("unknown system variable", stmt.Name())
+	}
+	v, literal := stmt.Value()
+	if err := s.ss.set(stmt.Name(), v, literal); err != nil {
+		return nil, err
+	}
+	return &Result{}, nil
+}
+
+// ShowVariablesStmt represents a Show Variables statement.
+type ShowVariablesStmt struct {
+	*Stmt
+}
+
+// NewShowVariablesStmt returns an instance of ShowVariablesStmt.
+// It implements Queryer interface.
+func NewShowVariablesStmt(stmt *Stmt) Queryer {
+	return &ShowVariablesStmt{stmt}
+}
+
+// Query executes a Show Variables query.
+// It returns the name and the value of the variables of the session, filtered by the like clause.
+func (s *ShowVariablesStmt) Query() (driver.Rows, error) {
+	// Casts statement.
+	stmt := s.p.(parser.ShowVariablesStmt)
+
+	if s.ss == nil {
+		return &Rows{}, nil
+	}
+	p, like := stmt.LikePattern()
+	var rs [][]driver.Value
+	for _, v := range s.ss.list() {
+		if like && !p.Match(v[0]) {
+			continue
+		}
+		rs = append(rs, []driver.Value{v[0], v[1]})
+	}
+	if len(rs) == 0 {
+		return &Rows{}, nil
+	}
+	return &Rows{
+		cols:  []string{"Variable_name", "Value"},
+		data:  rs,
+		size:  len(rs),
+		typed: s.typed,
+	}, nil
+}
+
+// formatSwitch returns the state of a switch as ON or OFF.
+func formatSwitch(on bool) string {
+	if on {
+		return switchOn
+	}
+	return switchOff
+}
+
+// ParseSwitch returns the state of a switch as ON or OFF, TRUE or FALSE, 1 or 0.
+// The second parameter is false if the value is not a switch.
+func ParseSwitch(s string) (on, ok bool) {
+	switch strings.ToUpper(s) {
+	case switchOn, "TRUE", "1":
+		return true, true
+	case switchOff, "FALSE", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// isDigits returns true if the string is only made of digits, as a date of the DURING clause.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
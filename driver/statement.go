@@ -1,13 +1,15 @@
 package driver
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
 	"fmt"
 	"hash/fnv"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
-	"unicode/utf8"
 
 	"github.com/jinzhu/now"
 	db "github.com/rvflash/awql-db"
@@ -17,20 +19,41 @@ import (
 )
 
 // Stmt is a prepared statement.
+// Without storage of snapshots, the materialized views are queried as the other views.
+// The warnings raised by its execution replace these of the previous statement.
 type Stmt struct {
-	si *awql.Stmt
-	db *db.Database
-	fc *cache.Cache
-	p  parser.Stmt
-	id string
+	si    *awql.Stmt
+	query string
+	db    *db.Database
+	fc    *cache.Cache
+	sc    *cache.Cache
+	wl    *warnings
+	ss    *session
+	mu    *sync.RWMutex
+	opts  *awql.Opts
+	p     parser.Stmt
+	id    string
+	typed bool
+	ads   bool
+	warns []error
 }
 
 // Bind applies the required argument replacements on the query.
 func (s *Stmt) Bind(args []driver.Value) error {
-	// Binds all arguments on the query.
-	if err := s.si.Bind(args); err != nil {
+	return s.BindNamed(namedValues(args))
+}
+
+// BindNamed applies the required argument replacements on the query.
+// Positional placeholders use the ordinal of the arguments, named placeholders their name.
+// The source query is kept as it is, the statement can be executed again with other arguments.
+func (s *Stmt) BindNamed(args []driver.NamedValue) error {
+	// Binds all arguments on the query, then the user variables of the session.
+	q, err := bind(s.query, args)
+	if err != nil {
 		return err
 	}
+	s.si.SrcQuery = s.ss.expand(q)
+
 	// Parses the statement to manage it as expected by Google Adwords.
 	stmts, err := parser.NewParser(strings.NewReader(s.si.SrcQuery)).Parse()
 	if err != nil {
@@ -50,36 +73,100 @@ func (s *Stmt) Close() error {
 }
 
 // NumInput returns the number of placeholder parameters.
+// It returns -1 with named placeholders.
 func (s *Stmt) NumInput() int {
-	return s.si.NumInput()
+	return numInput(s.query)
 }
 
 // Exec executes a query that doesn't return rows, such as an INSERT or UPDATE.
 func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+// ExecContext executes a query that doesn't return rows, such as an INSERT or UPDATE.
+// It implements the driver.StmtExecContext interface.
+func (s *Stmt) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
 	// Binds all arguments.
-	if err := s.Bind(args); err != nil {
+	if err := s.BindNamed(args); err != nil {
 		return nil, err
 	}
+	// Records the warnings of the statement.
+	s.warns = nil
+	defer func() { s.wl.set(s.warns) }()
+
 	// Executes query.
 	switch s.p.(type) {
 	case parser.CreateViewStmt:
+		// The database is shared by all the connections.
+		s.mu.Lock()
+		defer s.mu.Unlock()
 		return NewCreateViewStmt(s).Exec()
+	case parser.DropViewStmt:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return NewDropViewStmt(s).Exec()
+	case parser.RenameViewStmt:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return NewRenameViewStmt(s).Exec()
+	case *parser.RefreshViewStatement:
+		return NewRefreshViewStmt(s).Exec()
+	case parser.SetStmt:
+		return NewSetStmt(s).Exec()
 	}
-	return s.si.Exec(args)
+	return s.si.Exec(nil)
 }
 
 // Query sends request to Google Adwords API and retrieves its content.
 func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+// QueryContext sends request to Google Adwords API and retrieves its content.
+// It implements the driver.StmtQueryContext interface.
+func (s *Stmt) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
 	// Binds all arguments.
-	if err := s.Bind(args); err != nil {
+	if err := s.BindNamed(args); err != nil {
 		return nil, err
 	}
+	if _, ok := s.p.(parser.ShowWarningsStmt); ok {
+		// The warnings of the previous statement are kept.
+		return NewShowWarningsStmt(s).Query()
+	}
+	// Records the warnings of the statement.
+	s.warns = nil
+	defer func() { s.wl.set(s.warns) }()
+
 	// Executes query.
 	switch s.p.(type) {
 	case parser.DescribeStmt:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
 		return NewDescribeStmt(s).Query()
 	case parser.ShowStmt:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
 		return NewShowStmt(s).Query()
+	case parser.ShowSchemaChangesStmt:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return NewShowSchemaChangesStmt(s).Query()
+	case parser.CheckViewsStmt:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return NewCheckViewsStmt(s).Query()
+	case parser.ShowColumnsStmt:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return NewShowColumnsStmt(s).Query()
+	case parser.ShowCreateViewStmt:
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		return NewShowCreateViewStmt(s).Query()
+	case *parser.ShowVariablesStatement:
+		return NewShowVariablesStmt(s).Query()
+	case parser.ExplainStmt:
+		return NewExplainStmt(s).Query()
 	case parser.SelectStmt:
 		return NewSelectStmt(s).Query()
 	}
@@ -114,11 +201,26 @@ func (s *DescribeStmt) Query() (driver.Rows, error) {
 	stmt := s.p.(parser.DescribeStmt)
 
 	// Checks it the table exists.
-	tb, err := s.db.Table(stmt.SourceName())
+	tb, err := s.table(stmt.SourceName())
 	if err != nil {
 		return nil, err
 	}
 
+	if cols := stmt.Columns(); len(cols) > 0 {
+		// Gets properties on one specific column.
+		fd, err := tb.Field(cols[0].Name())
+		if err != nil {
+			return nil, err
+		}
+		return s.describe(tb, []parser.DynamicField{fd}, stmt.FullMode()), nil
+	}
+	// Gets properties of each columns of the table.
+	return s.describe(tb, tb.Columns(), stmt.FullMode()), nil
+}
+
+// describe returns the properties of these fields of the table, one by row.
+// With the full mode, it also adds their enum values and the columns with which they are not compatible.
+func (s *Stmt) describe(tb db.DataTable, fields []parser.DynamicField, full bool) *Rows {
 	// formatBool returns a string representation of a boolean.
 	var formatBool = func(ok bool) string {
 		if ok {
@@ -140,8 +242,7 @@ func (s *DescribeStmt) Query() (driver.Rows, error) {
 	}
 
 	// fieldNames returns the columns names.
-	var fieldNames = func(sizes []int) (cols []string) {
-		size := len(sizes)
+	var fieldNames = func(size int) (cols []string) {
 		if size == 0 {
 			return
 		}
@@ -149,30 +250,25 @@ func (s *DescribeStmt) Query() (driver.Rows, error) {
 		switch size {
 		case 6:
 			// Full mode
-			cols[4] = fmtColumnName("Enum", sizes[4])
-			cols[5] = fmtColumnName("Not_compatible_with", sizes[5])
+			cols[4] = "Enum"
+			cols[5] = "Not_compatible_with"
 			fallthrough
 		case 4:
 			// Default behavior
-			cols[0] = fmtColumnName("Field", sizes[0])
-			cols[1] = fmtColumnName("Type", sizes[1])
-			cols[2] = fmtColumnName("Key", sizes[2])
-			cols[3] = fmtColumnName("Supports_Zero_Impressions", sizes[3])
+			cols[0] = "Field"
+			cols[1] = "Type"
+			cols[2] = "Key"
+			cols[3] = "Supports_Zero_Impressions"
 		}
 		return cols
 	}
 
 	// fieldData returns the field properties.
-	var aggregateData = func(fields []parser.DynamicField, pk string, full bool) (data [][]driver.Value, sizes []int) {
+	var aggregateData = func(fields []parser.DynamicField, pk string, colSize int) (data [][]driver.Value) {
 		size := len(fields)
 		if size == 0 {
 			return
 		}
-		colSize := 4
-		if full {
-			colSize = 6
-		}
-		sizes = make([]int, colSize)
 		data = make([][]driver.Value, size)
 
 		// Computes information for each requested columns.
@@ -185,55 +281,45 @@ func (s *DescribeStmt) Query() (driver.Rows, error) {
 				// > Enum list
 				s := strings.Join(f.ValueList(), ", ")
 				data[i][4] = s
-				sizes[4] = maxLen(s, sizes[4])
 				// > Not compatible columns
 				u := strings.Join(f.NotCompatibleColumns(), ", ")
 				data[i][5] = u
-				sizes[5] = maxLen(u, sizes[5])
 				fallthrough
 			case 4:
 				// Default behavior
-				// > Column name
+				// > Column name, or its alias in a view.
 				n := f.Name()
+				if tb.IsView() && f.Alias() != "" {
+					n = f.Alias()
+				}
 				data[i][0] = n
-				sizes[0] = maxLen(n, sizes[0])
 				// > Type of field
 				t := f.Kind()
 				data[i][1] = t
-				sizes[1] = maxLen(t, sizes[1])
 				// > Key field
 				k := formatKey(f.IsSegment(), f.Name() == pk)
 				data[i][2] = k
-				sizes[2] = maxLen(k, sizes[2])
 				// > Zero impressions
 				z := formatBool(f.SupportsZeroImpressions())
 				data[i][3] = z
-				sizes[3] = maxLen(z, sizes[3])
 			}
 		}
 
-		return data, sizes
+		return data
 	}
 
-	var colSize []int
-	var data [][]driver.Value
-	if cols := stmt.Columns(); len(cols) > 0 {
-		// Gets properties on one specific column.
-		fd, err := tb.Field(cols[0].Name())
-		if err != nil {
-			return nil, err
-		}
-		data, colSize = aggregateData([]parser.DynamicField{fd}, tb.AggregateFieldName(), stmt.FullMode())
-	} else {
-		// Gets properties of each columns of the table.
-		data, colSize = aggregateData(tb.Columns(), tb.AggregateFieldName(), stmt.FullMode())
+	colSize := 4
+	if full {
+		colSize = 6
 	}
+	data := aggregateData(fields, tb.AggregateFieldName(), colSize)
 
 	return &Rows{
-		cols: fieldNames(colSize),
-		data: data,
-		size: len(data),
-	}, nil
+		cols:  fieldNames(colSize),
+		data:  data,
+		size:  len(data),
+		typed: s.typed,
+	}
 }
 
 // CreateViewStmt represents a Create statement.
@@ -252,30 +338,218 @@ func NewCreateViewStmt(stmt *Stmt) Execer {
 func (s *CreateViewStmt) Exec() (driver.Result, error) {
 	// Casts statement.
 	stmt := s.p.(*parser.CreateViewStatement)
+	// The snapshot of the replaced materialized view becomes useless.
+	key, _ := s.snapshotKey(stmt.SourceName())
 	if err := s.db.AddView(stmt); err != nil {
 		return nil, err
 	}
+	s.dropSnapshot(key)
+
+	return &Result{}, nil
+}
+
+// DropViewStmt represents a Drop View statement.
+type DropViewStmt struct {
+	*Stmt
+}
+
+// NewDropViewStmt returns an instance of DropViewStmt.
+// It implements Execer interface.
+func NewDropViewStmt(stmt *Stmt) Execer {
+	return &DropViewStmt{stmt}
+}
+
+// Exec executes a Drop View query.
+func (s *DropViewStmt) Exec() (driver.Result, error) {
+	// Casts statement.
+	stmt := s.p.(parser.DropViewStmt)
+	key, _ := s.snapshotKey(stmt.SourceName())
+	if err := s.db.DropView(stmt.SourceName()); err != nil {
+		if err != db.ErrUnknownTable || !stmt.IfExistsMode() {
+			return nil, err
+		}
+	}
+	s.dropSnapshot(key)
+
+	return &Result{}, nil
+}
+
+// RenameViewStmt represents a Rename View statement.
+type RenameViewStmt struct {
+	*Stmt
+}
+
+// NewRenameViewStmt returns an instance of RenameViewStmt.
+// It implements Execer interface.
+func NewRenameViewStmt(stmt *Stmt) Execer {
+	return &RenameViewStmt{stmt}
+}
+
+// Exec executes a Rename View query.
+func (s *RenameViewStmt) Exec() (driver.Result, error) {
+	// Casts statement.
+	stmt := s.p.(parser.RenameViewStmt)
+	key, _ := s.snapshotKey(stmt.SourceName())
+	if err := s.db.RenameView(stmt.SourceName(), stmt.DestinationName()); err != nil {
+		return nil, err
+	}
+	s.dropSnapshot(key)
+
+	return &Result{}, nil
+}
+
+// RefreshViewStmt represents a Refresh Materialized View statement.
+type RefreshViewStmt struct {
+	*Stmt
+}
+
+// NewRefreshViewStmt returns an instance of RefreshViewStmt.
+// It implements Execer interface.
+func NewRefreshViewStmt(stmt *Stmt) Execer {
+	return &RefreshViewStmt{stmt}
+}
+
+// Exec executes a Refresh Materialized View query.
+// The data of the view are requested and stored as its new snapshot.
+func (s *RefreshViewStmt) Exec() (driver.Result, error) {
+	// Casts statement.
+	stmt := s.p.(*parser.RefreshViewStatement)
+
+	s.mu.RLock()
+	t, err := s.db.Table(stmt.SourceName())
+	var key string
+	if err == nil {
+		key, err = s.snapshotKey(stmt.SourceName())
+	}
+	s.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.refreshView(t, key); err != nil {
+		return nil, err
+	}
 	return &Result{}, nil
 }
 
+// ShowCreateViewStmt represents a Show Create View statement.
+type ShowCreateViewStmt struct {
+	*Stmt
+}
+
+// NewShowCreateViewStmt returns an instance of ShowCreateViewStmt.
+// It implements Queryer interface.
+func NewShowCreateViewStmt(stmt *Stmt) Queryer {
+	return &ShowCreateViewStmt{stmt}
+}
+
+// Query executes a Show Create View query.
+// It returns the name of the view and the query to use to create it.
+func (s *ShowCreateViewStmt) Query() (driver.Rows, error) {
+	// Casts statement.
+	stmt := s.p.(parser.ShowCreateViewStmt)
+
+	vs, err := s.db.ViewStmt(stmt.ViewName())
+	if err != nil {
+		return nil, err
+	}
+	name, q := vs.SourceName(), vs.String()
+
+	return &Rows{
+		cols:  []string{"View", "Create View"},
+		data:  [][]driver.Value{{name, q}},
+		size:  1,
+		typed: s.typed,
+	}, nil
+}
+
+// summaryLabel is the value of the first column of the report summary.
+const summaryLabel = "Total"
+
 // dateFormat is the format of the date to use in Adwords API.
 const dateFormat = "20060102"
 
+// duringDates converts the during literal value into its range of dates.
+func duringDates(l string) (d []string) {
+	today := time.Now()
+	d = make([]string, 2)
+	switch l {
+	case "TODAY":
+		d[0], d[1] = today.Format(dateFormat), today.Format(dateFormat)
+	case "YESTERDAY":
+		yesterday := today.AddDate(0, 0, -1)
+		d[0], d[1] = yesterday.Format(dateFormat), yesterday.Format(dateFormat)
+	case "THIS_WEEK_SUN_TODAY":
+		sunday := now.Sunday()
+		d[0], d[1] = sunday.Format(dateFormat), today.Format(dateFormat)
+	case "THIS_WEEK_MON_TODAY":
+		monday := now.Monday()
+		d[0], d[1] = monday.Format(dateFormat), today.Format(dateFormat)
+	case "THIS_MONTH":
+		month := now.BeginningOfMonth()
+		d[0], d[1] = month.Format(dateFormat), today.Format(dateFormat)
+	case "LAST_WEEK":
+		now.FirstDayMonday = true
+		lastWeek := now.New(today.AddDate(0, 0, -7))
+		d[0] = lastWeek.BeginningOfWeek().Format(dateFormat)
+		d[1] = lastWeek.EndOfWeek().Format(dateFormat)
+	case "LAST_7_DAYS":
+		weekly := today.AddDate(0, 0, -7)
+		d[0], d[1] = weekly.Format(dateFormat), today.Format(dateFormat)
+	case "LAST_14_DAYS":
+		fortnight := today.AddDate(0, 0, -14)
+		d[0], d[1] = fortnight.Format(dateFormat), today.Format(dateFormat)
+	case "LAST_30_DAYS":
+		monthly := today.AddDate(0, 0, -30)
+		d[0], d[1] = monthly.Format(dateFormat), today.Format(dateFormat)
+	case "LAST_BUSINESS_WEEK":
+		now.FirstDayMonday = true
+		monday := now.New(today.AddDate(0, 0, -7)).BeginningOfWeek()
+		friday := monday.AddDate(0, 0, 5)
+		d[0], d[1] = monday.Format(dateFormat), friday.Format(dateFormat)
+	case "LAST_WEEK_SUN_SAT":
+		now.FirstDayMonday = false
+		sunday := now.New(today.AddDate(0, 0, -7))
+		d[0], d[1] = sunday.BeginningOfWeek().Format(dateFormat), sunday.EndOfWeek().Format(dateFormat)
+	}
+	return
+}
+
 // SelectStmt represents a Select statement.
+// With explain, its execution plan is returned instead of its result set.
 type SelectStmt struct {
 	*Stmt
+	explain bool
 }
 
 // NewSelectStmt returns an instance of SelectStmt.
 // It implements Queryer interface.
 func NewSelectStmt(stmt *Stmt) Queryer {
-	return &SelectStmt{stmt}
+	return &SelectStmt{Stmt: stmt}
 }
 
-// Hash builds a unique hash for this query and this Adwords ID.
+// Hash builds a unique hash for this query, this Adwords ID and this API version.
+// The reports with raw enum values, with summary or with zero impressions have their own hash.
 func (s *SelectStmt) Hash() string {
 	hash, _ := s.si.Hash()
-	return hash + "-" + s.id
+	hash += "-" + s.id
+	if v := s.ss.apiVersion(); v != "" {
+		hash += "-" + v
+	}
+	rawEnums, summary, zero := s.ss.get()
+	if rawEnums {
+		hash += "-raw"
+	}
+	if s.ads {
+		// The report summary is not supported and the zero impressions are excluded by the query.
+		return hash
+	}
+	if summary {
+		hash += "-summary"
+	}
+	if zero {
+		hash += "-zero"
+	}
+	return hash
 }
 
 // Query executes a SELECT query
@@ -283,6 +557,68 @@ func (s *SelectStmt) Hash() string {
 func (s *SelectStmt) Query() (driver.Rows, error) {
 	// Casts statement.
 	stmt := s.p.(*parser.SelectStatement)
+	q := s.si.SrcQuery
+
+	// Materialized view to query with its snapshot and the key of this one.
+	var (
+		mv   db.DataTable
+		skey string
+	)
+	// Views unwrapped and date range requested, to explain the query.
+	var (
+		chain  []string
+		during = append([]string(nil), stmt.During...)
+	)
+
+	// embellishExpression checks and types the computed column against the table.
+	// In a view, the operands become the columns of its data source with their default aggregate
+	// and the computed columns of the view are replaced by their expression.
+	var embellishExpression = func(expr *parser.Expression, alias string, t db.DataTable) (db.Field, error) {
+		col, err := db.NewComputedColumn(t, expr, alias)
+		if err != nil || !t.IsView() {
+			return col, err
+		}
+		var resolve func(e *parser.Expression) (*parser.Expression, error)
+		resolve = func(e *parser.Expression) (*parser.Expression, error) {
+			if e.Operator != "" {
+				l, err := resolve(e.Left)
+				if err != nil {
+					return nil, err
+				}
+				r, err := resolve(e.Right)
+				if err != nil {
+					return nil, err
+				}
+				return &parser.Expression{Operator: e.Operator, Left: l, Right: r}, nil
+			}
+			if e.IsNumber {
+				return e, nil
+			}
+			f, err := t.Field(e.Name())
+			if err != nil {
+				return nil, err
+			}
+			if sub, ok := f.Expression(); ok {
+				if _, ok := e.UseFunction(); ok {
+					// An aggregate can not apply on a computed column.
+					return nil, NewXError("invalid expression", e.String())
+				}
+				return sub, nil
+			}
+			leaf := &parser.Expression{Operand: f.Name(), Method: e.Method}
+			if leaf.Method == "" {
+				leaf.Method, _ = f.UseFunction()
+			}
+			return leaf, nil
+		}
+		e, err := resolve(expr)
+		if err != nil {
+			return nil, err
+		}
+		col.Head, col.Expr = e.String(), e.String()
+
+		return col, nil
+	}
 
 	// embellishField completes the field with data from the table.
 	var embellishField = func(c parser.DynamicField, t db.DataTable) (db.Field, error) {
@@ -294,14 +630,28 @@ func (s *SelectStmt) Query() (driver.Rows, error) {
 			}
 			return t.Field(c.Name())
 		}
+		if expr, ok := c.Expression(); ok {
+			// Keeps the expression of the statement as display name.
+			alias := c.Alias()
+			if alias == "" {
+				alias = c.Name()
+			}
+			return embellishExpression(expr, alias, t)
+		}
 		f, err := field(c, t)
 		if err != nil {
-			return nil, fmt.Errorf("%s (%v)", err, c.Name())
+			return nil, unknownColumn(c.Name(), t, parser.Position(q, c.Name()))
 		}
 		// Merges with statement to complete field's data.
+		// The default aggregate of a view's column applies if the statement has not its own.
 		cf := f.(db.Column)
-		cf.Method, _ = c.UseFunction()
-		cf.Unique = c.Distinct()
+		if method, ok := c.UseFunction(); ok {
+			if _, computed := cf.Expression(); computed {
+				return nil, NewXError("invalid expression", c.Name())
+			}
+			cf.Method = method
+		}
+		cf.Unique = cf.Unique || c.Distinct()
 		if alias := c.Alias(); alias != "" {
 			cf.Label = alias
 		}
@@ -323,6 +673,7 @@ func (s *SelectStmt) Query() (driver.Rows, error) {
 			}
 			if _, ok := fieldNames[field.Name()]; ok {
 				// Redundant field, skip it.
+				s.warn(NewXError("redundant column", field.Name()))
 				continue
 			}
 			fieldNames[field.Name()] = true
@@ -334,52 +685,8 @@ func (s *SelectStmt) Query() (driver.Rows, error) {
 	}
 
 	// embellishView adds more information on the statement about view.
+	// Its data source becomes the one of the view, a view on another view is unwrapped by the next call.
 	var embellishView = func(stmt *parser.SelectStatement, t db.DataTable) error {
-		// duringLiteralToDates converts during literal value into range of dates.
-		var duringLiteralToDates = func(l string) (d []string) {
-			today := time.Now()
-			d = make([]string, 2)
-			switch l {
-			case "TODAY":
-				d[0], d[1] = today.Format(dateFormat), today.Format(dateFormat)
-			case "YESTERDAY":
-				yesterday := today.AddDate(0, 0, -1)
-				d[0], d[1] = yesterday.Format(dateFormat), yesterday.Format(dateFormat)
-			case "THIS_WEEK_SUN_TODAY":
-				sunday := now.Sunday()
-				d[0], d[1] = sunday.Format(dateFormat), today.Format(dateFormat)
-			case "THIS_WEEK_MON_TODAY":
-				monday := now.Monday()
-				d[0], d[1] = monday.Format(dateFormat), today.Format(dateFormat)
-			case "THIS_MONTH":
-				month := now.BeginningOfMonth()
-				d[0], d[1] = month.Format(dateFormat), today.Format(dateFormat)
-			case "LAST_WEEK":
-				now.FirstDayMonday = true
-				lastWeek := now.New(today.AddDate(0, 0, -7))
-				d[0] = lastWeek.BeginningOfWeek().Format(dateFormat)
-				d[1] = lastWeek.EndOfWeek().Format(dateFormat)
-			case "LAST_7_DAYS":
-				weekly := today.AddDate(0, 0, -7)
-				d[0], d[1] = weekly.Format(dateFormat), today.Format(dateFormat)
-			case "LAST_14_DAYS":
-				fortnight := today.AddDate(0, 0, -14)
-				d[0], d[1] = fortnight.Format(dateFormat), today.Format(dateFormat)
-			case "LAST_30_DAYS":
-				monthly := today.AddDate(0, 0, -30)
-				d[0], d[1] = monthly.Format(dateFormat), today.Format(dateFormat)
-			case "LAST_BUSINESS_WEEK":
-				now.FirstDayMonday = true
-				monday := now.New(today.AddDate(0, 0, -7)).BeginningOfWeek()
-				friday := monday.AddDate(0, 0, 5)
-				d[0], d[1] = monday.Format(dateFormat), friday.Format(dateFormat)
-			case "LAST_WEEK_SUN_SAT":
-				now.FirstDayMonday = false
-				sunday := now.New(today.AddDate(0, 0, -7))
-				d[0], d[1] = sunday.BeginningOfWeek().Format(dateFormat), sunday.EndOfWeek().Format(dateFormat)
-			}
-			return
-		}
 		// inSelectClause returns true if the given field is in the select clause.
 		var inSelectClause = func(f parser.FieldPosition, fields []parser.DynamicField) bool {
 			for _, c := range fields {
@@ -399,48 +706,58 @@ func (s *SelectStmt) Query() (driver.Rows, error) {
 				return err
 			}
 		}
-		// FromClause.
+		// FromClause. The arguments are given to the parameters of the view.
 		view := t.SourceQuery()
+		src, err := s.db.Table(view.SourceName())
+		if err != nil {
+			return err
+		}
+		conds, err := viewConditions(t, src, stmt.Arguments())
+		if err != nil {
+			return err
+		}
 		stmt.TableName = view.SourceName()
-		// WhereClause. Merges it if it's possible. If not, returns zero result.
-		if len(stmt.ConditionList()) > 0 || len(view.ConditionList()) > 0 {
-			// Tries to merge the where clauses.
-			for _, vw := range view.ConditionList() {
-				for _, sw := range stmt.ConditionList() {
-					if vw.Name() == sw.Name() {
-						// To improve, prevents to go out of view's scope.
-						return ErrOutRange
-					}
-				}
-				stmt.Where = append(stmt.Where, vw)
+		stmt.Args = nil
+		// WhereClause. Uses the column names of the data source instead of the aliases of the view.
+		for i, c := range stmt.Where {
+			if f, err := t.Field(c.Name()); err == nil && f.Name() != c.Name() {
+				val, literal := c.Value()
+				stmt.Where[i] = newCondition(f.Name(), c.Operator(), val, literal)
 			}
 		}
+		// Intersects the conditions on the same column. If they are disjoint, returns zero result.
+		if len(conds) > 0 {
+			where, err := mergeConditions(stmt.ConditionList(), conds)
+			if err != nil {
+				return err
+			}
+			stmt.Where = where
+		}
 		// DuringClause. Merges it if it's possible. If not, returns zero result.
 		if vds := len(view.DuringList()); vds > 0 {
 			switch len(stmt.DuringList()) {
 			case 0:
 				stmt.During = view.DuringList()
 			case 1:
-				stmt.During = duringLiteralToDates(stmt.During[0])
+				stmt.During = duringDates(stmt.During[0])
 				fallthrough
 			default:
 				vd := view.DuringList()
 				if vds == 1 {
-					vd = duringLiteralToDates(vd[0])
+					vd = duringDates(vd[0])
 				}
-				if stmt.During[0] < vd[0] {
-					if stmt.During[0] > vd[1] {
-						return ErrOutRange
-					}
-					stmt.During[0] = vd[0]
-				} else if stmt.During[0] > vd[1] {
+				if stmt.During[0] > vd[1] || stmt.During[1] < vd[0] {
 					return ErrOutRange
 				}
+				clamped := false
+				if stmt.During[0] < vd[0] {
+					stmt.During[0], clamped = vd[0], true
+				}
 				if stmt.During[1] > vd[1] {
-					if stmt.During[1] < vd[0] {
-						return ErrOutRange
-					}
-					stmt.During[1] = vd[1]
+					stmt.During[1], clamped = vd[1], true
+				}
+				if clamped {
+					s.warn(NewXError("during clamped", t.SourceName()+" DURING "+strings.Join(stmt.During, ",")))
 				}
 			}
 		}
@@ -450,6 +767,8 @@ func (s *SelectStmt) Query() (driver.Rows, error) {
 			for _, gb := range view.GroupList() {
 				if inSelectClause(gb, stmt.Columns()) {
 					stmt.GroupBy = append(stmt.GroupBy, gb)
+				} else {
+					s.warn(NewXError("group by ignored", gb.Name()))
 				}
 			}
 		}
@@ -459,36 +778,110 @@ func (s *SelectStmt) Query() (driver.Rows, error) {
 			for _, ob := range view.OrderList() {
 				if inSelectClause(ob, stmt.Columns()) {
 					stmt.OrderBy = append(stmt.OrderBy, ob)
+				} else {
+					s.warn(NewXError("order by ignored", ob.Name()))
 				}
 			}
 		}
-		// LimitClause. Overloads bounds only in the limit of the view.
-		if view.StartIndex() > stmt.StartIndex() {
-			stmt.Offset = view.StartIndex()
-		}
+		// LimitClause. The bounds of the statement apply inside these of the view.
 		if rc, ok := view.PageSize(); ok {
-			if src, ok := stmt.PageSize(); ok && src > rc {
-				stmt.RowCount = rc
-			} else {
+			// Rows of the view remaining after the offset of the statement.
+			if rc -= stmt.StartIndex(); rc < 0 {
+				rc = 0
+			}
+			if src, ok := stmt.PageSize(); !ok || src > rc {
+				if ok {
+					s.warn(NewXError("limit overridden", t.SourceName()+" LIMIT "+strconv.Itoa(rc)))
+				}
 				stmt.RowCount = rc
 				stmt.WithRowCount = true
 			}
 		}
+		stmt.Offset += view.StartIndex()
 
 		return nil
 	}
 
+	// embellishSnapshot adds more information on the statement about the materialized view.
+	// Its snapshot becomes the data source, only the clauses of the statement apply on it.
+	var embellishSnapshot = func(stmt *parser.SelectStatement, t db.DataTable) error {
+		if len(stmt.Args) > 0 {
+			return NewXError("invalid parameter", stmt.Args[0].Name())
+		}
+		if len(stmt.During) > 0 {
+			// The date range is the one of the view, fixed by its snapshot.
+			return NewXError("invalid during", t.SourceName())
+		}
+		// SelectClause.
+		snap := snapshotTable(t)
+		if _, ok := stmt.Fields[0].UseFunction(); !ok && len(stmt.Fields) == 1 && stmt.Fields[0].Name() == "*" {
+			stmt.Fields = snap.Columns()
+		} else if err := embellishFields(stmt, snap); err != nil {
+			return err
+		}
+		// WhereClause. Uses the column names of the snapshot instead of the aliases of the view.
+		for i, c := range stmt.Where {
+			f, err := snap.Field(c.Name())
+			if err != nil {
+				return unknownColumn(c.Name(), snap, parser.Position(q, "WHERE", c.Name()))
+			}
+			val, literal := c.Value()
+			stmt.Where[i] = newCondition(f.Name(), c.Operator(), val, literal)
+		}
+		var err error
+		mv = t
+		skey, err = s.snapshotKey(t.SourceName())
+
+		return err
+	}
+
 	// embellish adds more information on the statement about table or view.
 	// Also manages special keywords and behavior like `*`.
+	// A view built on other views is unwrapped level by level, until the report
+	// or a materialized view, queried with its snapshot.
 	var embellish = func(stmt *parser.SelectStatement, t db.DataTable) error {
-		if t.IsView() {
-			return embellishView(stmt, t)
+		// The parameters are only allowed in the definition of a view.
+		for _, c := range stmt.Where {
+			if name, ok := parser.ParameterOf(c); ok {
+				return NewXError("invalid parameter", name)
+			}
 		}
-		return embellishFields(stmt, t)
+		if !t.IsView() {
+			if len(stmt.Args) > 0 {
+				return NewXError("invalid parameter", stmt.Args[0].Name())
+			}
+			if _, ok := schemaTable(t.SourceName()); ok {
+				// Replaces the all pattern with the list of the columns of the information schema.
+				if _, ok := stmt.Fields[0].UseFunction(); !ok && len(stmt.Fields) == 1 && stmt.Fields[0].Name() == "*" {
+					stmt.Fields = t.Columns()
+					return nil
+				}
+			}
+			return embellishFields(stmt, t)
+		}
+		views := make(map[string]bool)
+		for t.IsView() {
+			if views[t.SourceName()] {
+				return db.ErrViewCycle
+			}
+			views[t.SourceName()] = true
+			chain = append(chain, t.SourceName())
+			if _, ok := t.Materialized(); ok && s.sc != nil {
+				return embellishSnapshot(stmt, t)
+			}
+			if err := embellishView(stmt, t); err != nil {
+				return err
+			}
+			var err error
+			if t, err = s.db.Table(stmt.SourceName()); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
 	// fieldNames replaces the display name of columns by their names or alias if exist.
-	var fieldNames = func(columns []parser.DynamicField, sizes []int) []string {
+	var fieldNames = func(columns []parser.DynamicField) []string {
 		cols := make([]string, len(columns))
 		for i, c := range columns {
 			if c.Alias() != "" {
@@ -496,51 +889,132 @@ func (s *SelectStmt) Query() (driver.Rows, error) {
 			} else {
 				cols[i] = c.Name()
 			}
-			// Formats the column name in order to reflect the maximum length of the column.
-			cols[i] = fmtColumnName(cols[i], sizes[i])
 		}
 		return cols
 	}
 
+	// fields returns the properties of each column.
+	var fields = func(columns []parser.DynamicField) []db.Field {
+		fields := make([]db.Field, len(columns))
+		for i, c := range columns {
+			fields[i], _ = c.(db.Field)
+		}
+		return fields
+	}
+
 	// Adds more detail on each columns (kind, etc.).
-	t, err := s.db.Table(stmt.SourceName())
-	if err != nil {
-		return nil, err
+	// The tables of the information schema are built locally, with the tables of the database.
+	_, schema := schemaTable(stmt.SourceName())
+	rawEnums, summary, zero := s.ss.get()
+	s.mu.RLock()
+	t, err := s.table(stmt.SourceName())
+	if err == nil {
+		err = embellish(stmt, t)
+	}
+	if err == nil && mv == nil {
+		// Checks the query against the report, to not wait the error of Adwords.
+		if t, err = s.table(stmt.SourceName()); err == nil {
+			var warns []error
+			if warns, err = validate(stmt, t, q, zero && !schema); err == nil {
+				s.warns = append(s.warns, warns...)
+			}
+		}
+	}
+	s.mu.RUnlock()
+	if err == ErrOutRange || err == ErrDisjoint {
+		// Out of the scope of the view or with disjoint conditions, the result set is empty without requesting Adwords.
+		if s.explain {
+			return s.plan(stmt, queryPlan{
+				views: chain, during: during, outRange: err == ErrOutRange, disjoint: err == ErrDisjoint,
+			})
+		}
+		return &Rows{cols: fieldNames(stmt.Columns()), fields: fields(stmt.Columns()), typed: s.typed}, nil
 	}
-	if err = embellish(stmt, t); err != nil {
+	if err != nil {
 		return nil, err
 	}
 
 	// Keeps only accepted Adwords Awql grammar as query.
-	s.si.SrcQuery = stmt.LegacyString()
+	// With the Google Ads API, the query is translated in GAQL.
+	var gaqlColumns []gaqlColumn
+	if s.ads && mv == nil && !schema {
+		if s.si.SrcQuery, gaqlColumns, err = gaqlQuery(stmt, t, zero); err != nil {
+			return nil, err
+		}
+	} else {
+		s.si.SrcQuery = stmt.LegacyString()
+	}
+	if s.explain {
+		return s.plan(stmt, queryPlan{views: chain, during: during, mv: mv, skey: skey, schema: schema})
+	}
 
-	// Tries to retrieve data in cache.
+	// Tries to retrieve data in the snapshot of the materialized view, in the database or in cache.
+	// The report summary, the last record, is only requested to Adwords.
 	var records [][]string
-	if records, err = s.fc.Get(s.Hash()); err != nil {
-		// Requests the Adwords API without any args, binding already done.
+	summary = summary && !s.ads && mv == nil && !schema
+	if mv != nil {
+		if records, err = s.snapshot(stmt, mv, skey); err != nil {
+			return nil, err
+		}
+	} else if schema {
+		s.mu.RLock()
+		records, err = filterRecords(stmt, s.schema(t))
+		s.mu.RUnlock()
+		if err != nil {
+			return nil, err
+		}
+	} else if records, err = s.fc.Get(s.Hash()); err != nil && s.ads {
+		// Requests the Google Ads API with the GAQL query.
+		if records, err = s.si.Db.SearchStream(s.si.SrcQuery, gaqlFieldNames(gaqlColumns)); err != nil {
+			return nil, err
+		}
+		var warns []error
+		records, warns = gaqlRecords(records, gaqlColumns, rawEnums)
+		s.warns = append(s.warns, warns...)
+		// Saves the data in cache.
+		go s.fc.Set(&cache.Item{Key: s.Hash(), Value: records})
+	} else if err != nil {
+		// Requests the Adwords API without any args, binding already done,
+		// with the settings of the session.
+		s.opts.UseRawEnumValues = rawEnums
+		s.opts.IncludeZeroImpressions = zero
+		s.opts.SkipReportSummary = !summary
 		var rows driver.Rows
 		if rows, err = s.si.Query(nil); err != nil {
 			return nil, err
 		}
-		records = rows.(*awql.Rows).Data
-		// Saves the data in cache.
+		// Ignores the column header, the columns are known.
+		ar := rows.(*awql.Rows)
+		records = ar.Data[ar.Position:]
+		// Saves the data in cache, with the report summary.
 		go s.fc.Set(&cache.Item{Key: s.Hash(), Value: records})
 	}
+	var total []string
+	if summary && len(records) > 0 {
+		total, records = records[len(records)-1], records[:len(records)-1]
+	}
 
 	// Aggregates rows by columns if needed.
-	data, colSize, err := aggregateData(stmt, records)
+	data, err := aggregateData(stmt, records)
 	if err != nil {
 		return nil, err
 	}
 	// Initialises the result set.
-	size := len(data)
-	if size == 0 {
-		return nil, nil
-	}
 	rs := &Rows{
-		cols: fieldNames(stmt.Columns(), colSize),
-		data: data,
-		size: size,
+		cols:   fieldNames(stmt.Columns()),
+		fields: fields(stmt.Columns()),
+		data:   data,
+		size:   len(data),
+		typed:  s.typed,
+	}
+	if total != nil {
+		// The report summary is the next result set.
+		if rs.next, err = summaryRows(stmt, total, s.typed); err != nil {
+			return nil, err
+		}
+	}
+	if rs.size == 0 {
+		return rs, nil
 	}
 	// Sorts rows by columns.
 	if len(stmt.OrderList()) > 0 {
@@ -554,10 +1028,46 @@ func (s *SelectStmt) Query() (driver.Rows, error) {
 	return rs, nil
 }
 
+// summaryRows returns the report summary as a result set of one row, with the columns of the statement.
+// The totals computed by Adwords are kept as they are, without grouping, and the counts are null.
+// The label Total of the first column is also replaced by a null value.
+func summaryRows(stmt *parser.SelectStatement, total []string, typed bool) (*Rows, error) {
+	record := append([]string(nil), total...)
+	if record[0] == summaryLabel {
+		record[0] = doubleDash
+	}
+
+	ts := *stmt
+	ts.GroupBy = nil
+	data, err := aggregateData(&ts, [][]string{record})
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]db.Field, len(stmt.Columns()))
+	for i, c := range stmt.Columns() {
+		fields[i], _ = c.(db.Field)
+		if method, _ := c.UseFunction(); method == "COUNT" && len(data) > 0 {
+			data[0][i] = NullString{}
+		}
+	}
+	rs := &Rows{
+		cols:   make([]string, len(stmt.Columns())),
+		fields: fields,
+		data:   data,
+		size:   len(data),
+		typed:  typed,
+	}
+	for i, c := range stmt.Columns() {
+		if rs.cols[i] = c.Alias(); rs.cols[i] == "" {
+			rs.cols[i] = c.Name()
+		}
+	}
+	return rs, nil
+}
+
 // aggregateData aggregates records as expected by the statement.
-// Returns aggregated lines with maximum size of each column.
 // An error occurred if we fail to parse records.
-func aggregateData(stmt parser.SelectStmt, records [][]string) ([][]driver.Value, []int, error) {
+func aggregateData(stmt *parser.SelectStatement, records [][]string) ([][]driver.Value, error) {
 	// autoValue trims prefixes `auto` and returns a cleaned string.
 	// Also indicates with the second parameter, if it's a automatic value or not.
 	var autoValued = func(s string) (v string, ok bool) {
@@ -573,58 +1083,6 @@ func aggregateData(stmt parser.SelectStmt, records [][]string) ([][]driver.Value
 		}
 		return
 	}
-	// lenFloat64 returns the length of the float by calculating the number of digit + point.
-	var lenFloat64 = func(f AggregatedNullFloat64) (i int) {
-		if i = len(f.Layout); i > 0 {
-			// It's a datetime.
-			return
-		}
-		switch {
-		case f.NullFloat64.Float64 >= 1000000000000:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 100000000000:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 10000000000:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 1000000000:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 100000000:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 10000000:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 1000000:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 100000:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 10000:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 1000:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 100:
-			i++
-			fallthrough
-		case f.NullFloat64.Float64 >= 10:
-			i++
-			fallthrough
-		default:
-			i++
-		}
-		if f.Precision > 0 {
-			// Manages `.01`
-			i += f.Precision + 1
-		}
-		return
-	}
 	// parsePercentNullFloat64 parses a string and returns it as double that can be a percentage.
 	var parsePercentNullFloat64 = func(s string) (d PercentNullFloat64, err error) {
 		if s == doubleDash {
@@ -725,6 +1183,73 @@ func aggregateData(stmt parser.SelectStmt, records [][]string) ([][]driver.Value
 		}
 		return
 	}
+	// operandValue parses the value of an operand of a computed column.
+	var operandValue = func(s string) (float64, bool) {
+		s, _ = autoValued(s)
+		d, err := parsePercentNullFloat64(s)
+		return d.NullFloat64.Float64, err == nil && d.NullFloat64.Valid
+	}
+	// computed returns the expression of the computed column.
+	// The second parameter is true if one of its operands is aggregated.
+	var computed = func(c parser.DynamicField) (expr *parser.Expression, aggr bool) {
+		if expr, _ = c.Expression(); expr == nil {
+			return
+		}
+		for _, o := range expr.Operands() {
+			if _, ok := o.UseFunction(); ok {
+				aggr = true
+			}
+		}
+		return
+	}
+	// formatFloat returns the string representation of a computed value of this kind.
+	var formatFloat = func(f float64, ok bool, kind string) string {
+		switch {
+		case !ok:
+			return doubleDash
+		case strings.ToUpper(kind) == "DOUBLE":
+			return strconv.FormatFloat(f, 'f', 2, 64)
+		}
+		return strconv.FormatFloat(f, 'f', 0, 64)
+	}
+	// operand represents the aggregated value of an operand, with the number of values seen.
+	type operand struct {
+		v  float64
+		n  int
+		ok bool
+	}
+	// accumulate applies the aggregate method of the operand on its current value.
+	// Without method, the last value is kept.
+	var accumulate = func(o *parser.Expression, a operand, s string) operand {
+		method, _ := o.UseFunction()
+		if method == "COUNT" {
+			a.v, a.n, a.ok = a.v+1, a.n+1, true
+			return a
+		}
+		f, ok := operandValue(s)
+		if !ok {
+			// Nil value, skip it.
+			return a
+		}
+		switch method {
+		case "AVG":
+			a.v = (a.v*float64(a.n) + f) / float64(a.n+1)
+		case "MAX":
+			if !a.ok || a.v < f {
+				a.v = f
+			}
+		case "MIN":
+			if !a.ok || a.v > f {
+				a.v = f
+			}
+		case "SUM":
+			a.v += f
+		default:
+			a.v = f
+		}
+		a.n, a.ok = a.n+1, true
+		return a
+	}
 	// hash returns a numeric hash for the given string.
 	var hash = func(s string) uint64 {
 		h := fnv.New64a()
@@ -740,6 +1265,8 @@ func aggregateData(stmt parser.SelectStmt, records [][]string) ([][]driver.Value
 				ok = true
 			} else if _, use := c.UseFunction(); use {
 				ok = true
+			} else if _, use := computed(c); use {
+				ok = true
 			}
 		}
 		return
@@ -750,11 +1277,43 @@ func aggregateData(stmt parser.SelectStmt, records [][]string) ([][]driver.Value
 	groupSize := len(stmt.GroupList())
 	columnSize := len(stmt.Columns())
 
+	// Positions of the columns in the records, as requested in the legacy query.
+	index := make(map[string]int)
+	for p, name := range stmt.LegacyColumns() {
+		index[name] = p
+	}
+	// Operands of the computed columns to aggregate by group.
+	var operands []*parser.Expression
+	for _, c := range stmt.Columns() {
+		if expr, aggr := computed(c); aggr {
+			operands = append(operands, expr.Operands()...)
+		}
+	}
+	// values returns the values of the record in the order of the statement's columns.
+	// The computed columns without aggregate are evaluated, the others are computed by group.
+	var values = func(r []string) []string {
+		v := make([]string, columnSize)
+		for i, c := range stmt.Columns() {
+			expr, aggr := computed(c)
+			switch {
+			case expr == nil:
+				v[i] = r[index[c.Name()]]
+			case !aggr:
+				f, ok := expr.Eval(func(o string) (float64, bool) {
+					return operandValue(r[index[o]])
+				})
+				v[i] = formatFloat(f, ok, c.(db.Field).Kind())
+			}
+		}
+		return v
+	}
+
 	// Builds a map with group values as key.
 	var data map[string][]driver.Value
 	data = make(map[string][]driver.Value)
-	cs := make([]int, columnSize)
-	for p, f := range records {
+	aggr := make(map[string]map[string]operand)
+	for p, r := range records {
+		f := values(r)
 		// Picks the aggregate values.
 		var group []uint64
 		if groupSize > 0 {
@@ -770,9 +1329,21 @@ func aggregateData(stmt parser.SelectStmt, records [][]string) ([][]driver.Value
 		}
 		key := fmt.Sprint(group)
 
+		// Aggregates the operands of the computed columns.
+		if len(operands) > 0 && aggr[key] == nil {
+			aggr[key] = make(map[string]operand)
+		}
+		for _, o := range operands {
+			aggr[key][o.String()] = accumulate(o, aggr[key][o.String()], r[index[o.Name()]])
+		}
+
 		// Converts string slice of the row as expected by SQL driver.
 		row := make([]driver.Value, columnSize)
 		for i, c := range stmt.Columns() {
+			if _, ok := computed(c); ok {
+				// Computed once all the rows of the group are aggregated.
+				continue
+			}
 			if method, ok := c.UseFunction(); ok {
 				// Retrieves the aggregate value if already set.
 				var v AggregatedNullFloat64
@@ -783,15 +1354,13 @@ func aggregateData(stmt parser.SelectStmt, records [][]string) ([][]driver.Value
 					// Increments the counter.
 					v.NullFloat64.Float64++
 					v.NullFloat64.Valid = true
-					// Calculates the length of the column.
-					cs[i] = lenFloat64(v)
 					row[i] = v
 					continue
 				}
 				// Casts to float the current column's value.
 				cv, err := aggregate(f[i], c.(db.Field).Kind())
 				if err != nil {
-					return nil, nil, err
+					return nil, err
 				}
 				if !cv.NullFloat64.Valid {
 					// Nil value, skip it.
@@ -823,22 +1392,36 @@ func aggregateData(stmt parser.SelectStmt, records [][]string) ([][]driver.Value
 				if strings.ToUpper(c.(db.Field).Kind()) == "DOUBLE" {
 					v.Precision = 2
 				}
-				// Calculates the size of the column in order to keep its max length.
-				cs[i] = lenFloat64(v)
 				row[i] = v
 			} else {
 				v, err := cast(f[i], c.(db.Field).Kind())
 				if err != nil {
-					return nil, nil, err
+					return nil, err
 				}
-				// Calculates the size of the column in order to keep its max length.
-				cs[i] = maxLen(f[i], cs[i])
 				row[i] = v
 			}
 		}
 		data[key] = row
 	}
 
+	// Computes the columns using aggregated operands.
+	for key, row := range data {
+		for i, c := range stmt.Columns() {
+			expr, ok := computed(c)
+			if !ok {
+				continue
+			}
+			f, ok := expr.Eval(func(o string) (float64, bool) {
+				return aggr[key][o].v, aggr[key][o].ok
+			})
+			v := AggregatedNullFloat64{NullFloat64: sql.NullFloat64{Float64: f, Valid: ok}}
+			if strings.ToUpper(c.(db.Field).Kind()) == "DOUBLE" {
+				v.Precision = 2
+			}
+			row[i] = v
+		}
+	}
+
 	// Builds the result set.
 	rs := make([][]driver.Value, len(data))
 	var i int
@@ -846,7 +1429,7 @@ func aggregateData(stmt parser.SelectStmt, records [][]string) ([][]driver.Value
 		rs[i] = r
 		i++
 	}
-	return rs, cs, nil
+	return rs, nil
 }
 
 // lessFunc
@@ -919,18 +1502,23 @@ func (s *ShowStmt) Query() (driver.Rows, error) {
 	stmt := s.p.(parser.ShowStmt)
 
 	// fieldNames returns the columns names.
-	var fieldNames = func(version string, sizes []int) (cols []string) {
-		nbCol := len(sizes)
+	var fieldNames = func(version string, nbCol int) (cols []string) {
 		if nbCol == 0 {
 			return
 		}
 		cols = make([]string, nbCol)
 		switch nbCol {
+		case 4:
+			cols[3] = "Last_refresh"
+			fallthrough
+		case 3:
+			cols[2] = "Table_origin"
+			fallthrough
 		case 2:
-			cols[1] = fmtColumnName("Table_type", sizes[1])
+			cols[1] = "Table_type"
 			fallthrough
 		case 1:
-			cols[0] = fmtColumnName("Tables_in_"+version, sizes[0])
+			cols[0] = "Tables_in_" + version
 		}
 		return cols
 	}
@@ -964,13 +1552,19 @@ func (s *ShowStmt) Query() (driver.Rows, error) {
 	}
 	nbCol := 1
 	if stmt.FullMode() {
-		nbCol++
+		// Adds the type and the origin of the tables, with the last refresh of the materialized views.
+		nbCol += 3
 	}
-	cs := make([]int, nbCol)
 	rs := make([][]driver.Value, size)
 	for i := 0; i < size; i++ {
 		rs[i] = make([]driver.Value, nbCol)
 		switch nbCol {
+		case 4:
+			rs[i][3] = s.lastRefresh(tables[i])
+			fallthrough
+		case 3:
+			rs[i][2] = tables[i].Origin()
+			fallthrough
 		case 2:
 			var kind string
 			if tables[i].IsView() {
@@ -979,30 +1573,15 @@ func (s *ShowStmt) Query() (driver.Rows, error) {
 				kind = "BASE TABLE"
 			}
 			rs[i][1] = kind
-			cs[1] = maxLen(kind, cs[1])
 			fallthrough
 		case 1:
 			rs[i][0] = tables[i].SourceName()
-			cs[0] = maxLen(tables[i].SourceName(), cs[0])
 		}
 	}
 	return &Rows{
-		cols: fieldNames(s.db.Version, cs),
-		data: rs,
-		size: size,
+		cols:  fieldNames(s.db.Version, nbCol),
+		data:  rs,
+		size:  size,
+		typed: s.typed,
 	}, nil
 }
-
-// fmtColumnName returns the name of the column formatted as expected to display it.
-func fmtColumnName(name string, minLen int) string {
-	return fmt.Sprintf("%-"+strconv.Itoa(maxLen(name, minLen))+"v", name)
-}
-
-// maxLen returns the length in runes of the string, only if it is more bigger than minLen.
-func maxLen(name string, minLen int) (len int) {
-	len = utf8.RuneCountInString(name)
-	if len < minLen {
-		len = minLen
-	}
-	return
-}
@@ -0,0 +1,33 @@
+package driver
+
+import (
+	"testing"
+
+	awql "github.com/rvflash/awql-driver"
+)
+
+// TestSelectStmt_Hash tests the method named Hash on SelectStmt struct.
+func TestSelectStmt_Hash(t *testing.T) {
+	const q = "SELECT CampaignName FROM CAMPAIGN_PERFORMANCE_REPORT"
+	// Returns the hash of the query with a session using this API version and the raw enum values.
+	var hash = func(version string, rawEnums bool) string {
+		cfg := NewConfig("123-456-7890")
+		cfg.APIVersion = version
+		cfg.UseRawEnumValues = rawEnums
+		s := &SelectStmt{Stmt: &Stmt{si: &awql.Stmt{SrcQuery: q}, ss: newSession(cfg), id: cfg.AdwordsID}}
+		return s.Hash()
+	}
+	var hashTests = []struct {
+		h1, h2 string
+		eq     bool
+	}{
+		{h1: hash("v201809", false), h2: hash("v201809", false), eq: true},
+		{h1: hash("v201809", false), h2: hash("v201806", false)},
+		{h1: hash("v201809", false), h2: hash("v201809", true)},
+	}
+	for i, tt := range hashTests {
+		if eq := tt.h1 == tt.h2; eq != tt.eq {
+			t.Errorf("%d. Expected %v, received %v (%s, %s)", i, tt.eq, eq, tt.h1, tt.h2)
+		}
+	}
+}
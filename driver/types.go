@@ -3,7 +3,9 @@ package driver
 import (
 	"database/sql"
 	"database/sql/driver"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -124,3 +126,80 @@ func (n Time) Value() (driver.Value, error) {
 	}
 	return n.Time.Format(n.Layout), nil
 }
+
+// NativeValuer is the interface implemented by values that can be represented
+// with one of the Go types expected by the database/sql package.
+type NativeValuer interface {
+	NativeValue() driver.Value
+}
+
+// NativeValue returns the value as float64 or nil if it is not set.
+// It implements the NativeValuer interface.
+func (n PercentNullFloat64) NativeValue() driver.Value {
+	if !n.NullFloat64.Valid {
+		return nil
+	}
+	return n.NullFloat64.Float64
+}
+
+// NativeValue returns the value as int64 or nil if it is not set or excluded.
+// It implements the NativeValuer interface.
+func (n AutoExcludedNullInt64) NativeValue() driver.Value {
+	if n.Excluded || !n.NullInt64.Valid {
+		return nil
+	}
+	return n.NullInt64.Int64
+}
+
+// NativeValue returns the value as float64, as time.Time if it's a date, or nil.
+// It implements the NativeValuer interface.
+func (n AggregatedNullFloat64) NativeValue() driver.Value {
+	if !n.NullFloat64.Valid {
+		return nil
+	}
+	if n.Layout != "" {
+		return time.Unix(int64(n.NullFloat64.Float64), 0).UTC()
+	}
+	return n.NullFloat64.Float64
+}
+
+// NativeValue returns the value as string or nil if it is not set.
+// It implements the NativeValuer interface.
+func (n NullString) NativeValue() driver.Value {
+	if !n.Valid {
+		return nil
+	}
+	return n.String
+}
+
+// NativeValue returns the value as time.Time or nil if it is not set.
+// It implements the NativeValuer interface.
+func (n Time) NativeValue() driver.Value {
+	if n.Time.IsZero() {
+		return nil
+	}
+	return n.Time
+}
+
+// scanType returns the Go type used to represent in typed mode a value of this Adwords kind.
+// With aggregate function, the value is a float, except for the dates.
+func scanType(kind, method string) reflect.Type {
+	switch strings.ToUpper(kind) {
+	case "BID", "INT", "INTEGER", "LONG", "MONEY":
+		if method != "" {
+			return reflect.TypeOf(sql.NullFloat64{})
+		}
+		return reflect.TypeOf(sql.NullInt64{})
+	case "DOUBLE":
+		return reflect.TypeOf(sql.NullFloat64{})
+	case "DATE", "DATETIME":
+		if method == "COUNT" {
+			return reflect.TypeOf(sql.NullFloat64{})
+		}
+		return reflect.TypeOf(sql.NullTime{})
+	}
+	if method != "" {
+		return reflect.TypeOf(sql.NullFloat64{})
+	}
+	return reflect.TypeOf(sql.NullString{})
+}
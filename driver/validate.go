@@ -0,0 +1,164 @@
+package driver
+
+import (
+	"strconv"
+	"strings"
+
+	db "github.com/rvflash/awql-db"
+	parser "github.com/rvflash/awql-parser"
+)
+
+// validate checks the legacy query of the statement against its report before sending it to Adwords.
+// The columns must exist and be compatible together, the conditions must use operators and values
+// supported by their columns. The source query q is used to locate the errors.
+// With the support of zero impressions, a warning is returned for each column not supporting it.
+func validate(stmt *parser.SelectStatement, t db.DataTable, q string, zero bool) (warnings []error, err error) {
+	// Lists the columns used by the query, selected or filtered.
+	var fields []db.Field
+	seen := make(map[string]bool)
+	for _, name := range stmt.LegacyColumns() {
+		f, err := t.Field(name)
+		if err != nil {
+			return nil, unknownColumn(name, t, parser.Position(q, name))
+		}
+		seen[name] = true
+		fields = append(fields, f)
+	}
+	for _, c := range stmt.Where {
+		f, err := t.Field(c.Name())
+		if err != nil {
+			return nil, unknownColumn(c.Name(), t, parser.Position(q, "WHERE", c.Name()))
+		}
+		if err := checkCondition(c, f, q); err != nil {
+			return nil, err
+		}
+		if !seen[c.Name()] {
+			seen[c.Name()] = true
+			fields = append(fields, f)
+		}
+	}
+
+	// Rejects the columns which can not be requested together.
+	for i, f := range fields {
+		for _, g := range fields[i+1:] {
+			if containsValue(f.NotCompatibleColumns(), g.Name()) || containsValue(g.NotCompatibleColumns(), f.Name()) {
+				return nil, NewPosError("incompatible columns", f.Name()+", "+g.Name(), parser.Position(q, g.Name()), "")
+			}
+		}
+	}
+
+	// Warns about the columns without rows with zero impressions.
+	if zero {
+		for _, f := range fields {
+			if !f.SupportsZeroImpressions() {
+				warnings = append(warnings, NewXError("zero impressions not supported", f.Name()))
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// checkCondition checks the operator and the values of the condition against the kind of its column.
+// The numeric columns can not use the string operators and expect numbers as values,
+// the enum columns only support the equality or the list operators with values of their list.
+func checkCondition(c parser.Condition, f db.Field, q string) error {
+	list := f.ValueList()
+	numeric := isNumeric(f.Kind())
+	switch c.Operator() {
+	case opEqual, opDifferent, opIn, opNotIn:
+	case opSuperior, opSuperiorOrEqual, opInferior, opInferiorOrEqual:
+		if len(list) > 0 {
+			return NewPosError("invalid operator", c.Operator(), parser.Position(q, c.Name(), c.Operator()), "")
+		}
+	default:
+		if numeric || len(list) > 0 {
+			return NewPosError("invalid operator", c.Operator(), parser.Position(q, c.Name(), c.Operator()), "")
+		}
+		return nil
+	}
+	values, _ := c.Value()
+	for _, v := range values {
+		switch {
+		case numeric:
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return NewPosError("invalid value", v, parser.Position(q, c.Name(), v), "")
+			}
+		case len(list) > 0:
+			if !containsValue(list, v) {
+				return NewPosError("invalid value", v, parser.Position(q, c.Name(), v), closest(v, list))
+			}
+		}
+	}
+	return nil
+}
+
+// isNumeric returns true if the values of this kind are numbers.
+func isNumeric(kind string) bool {
+	switch strings.ToUpper(kind) {
+	case "BID", "INT", "INTEGER", "LONG", "MONEY", "DOUBLE":
+		return true
+	}
+	return false
+}
+
+// unknownColumn returns the error of a column unknown in the table,
+// located at this position and with the closest column name of the table as hint.
+// In a view, the alias names are used instead of the column names.
+func unknownColumn(name string, t db.DataTable, pos int) error {
+	var names []string
+	for _, c := range t.Columns() {
+		if t.IsView() && c.Alias() != "" {
+			names = append(names, c.Alias())
+		} else {
+			names = append(names, c.Name())
+		}
+	}
+	return NewPosError("unknown column", name, pos, closest(name, names))
+}
+
+// closest returns the name of the list the closest to this one, ignoring the case.
+// An empty string is returned if none is close enough to be a typo.
+func closest(name string, list []string) (s string) {
+	// The number of edits allowed grows with the length of the name.
+	max := len(name) / 3
+	if max < 2 {
+		max = 2
+	}
+	for _, n := range list {
+		if d := distance(strings.ToLower(name), strings.ToLower(n)); d <= max {
+			s, max = n, d-1
+		}
+	}
+	return
+}
+
+// distance returns the Levenshtein distance between the strings a and b:
+// the number of runes to insert, delete or substitute to change one into the other.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range ra {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i + 1
+		for j := range rb {
+			cost := 1
+			if ra[i] == rb[j] {
+				cost = 0
+			}
+			cur[j+1] = minInt(prev[j]+cost, minInt(prev[j+1]+1, cur[j]+1))
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// minInt returns the smallest of the two integers.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,108 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"strconv"
+	"strings"
+	"sync"
+
+	parser "github.com/rvflash/awql-parser"
+)
+
+// warnings is the list of the warnings raised by the last statement of a connection.
+// Each connection has its own list, as SHOW WARNINGS only returns these of its connection.
+type warnings struct {
+	list []error
+	mu   sync.Mutex
+}
+
+// get returns the warnings of the last statement.
+func (w *warnings) get() []error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.list
+}
+
+// set replaces the warnings of the last statement.
+func (w *warnings) set(list []error) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	w.list = list
+	w.mu.Unlock()
+}
+
+// warn records a warning on the statement.
+func (s *Stmt) warn(err error) {
+	s.warns = append(s.warns, err)
+}
+
+// warningCode returns the code of the warning, the upper case text of its error.
+func warningCode(err error) string {
+	if e, ok := err.(*Error); ok {
+		return e.s
+	}
+	// DatabaseError.UNKNOWN_COLUMN (name)
+	s := err.Error()
+	if p := strings.Index(s, "."); p > -1 {
+		s = s[p+1:]
+	}
+	if p := strings.Index(s, " "); p > -1 {
+		s = s[:p]
+	}
+	return s
+}
+
+// ShowWarningsStmt represents a Show Warnings statement.
+type ShowWarningsStmt struct {
+	*Stmt
+}
+
+// NewShowWarningsStmt returns an instance of ShowWarningsStmt.
+// It implements Queryer interface.
+func NewShowWarningsStmt(stmt *Stmt) Queryer {
+	return &ShowWarningsStmt{stmt}
+}
+
+// Query executes a Show Warnings query.
+// It returns the level, the code and the message of each warning raised by the last statement,
+// or only their number with the count function.
+func (s *ShowWarningsStmt) Query() (driver.Rows, error) {
+	// Casts statement.
+	stmt := s.p.(parser.ShowWarningsStmt)
+
+	list := s.wl.get()
+	if stmt.CountOnly() {
+		n := strconv.Itoa(len(list))
+		var v driver.Value = n
+		if s.typed {
+			v = int64(len(list))
+		}
+		return &Rows{
+			cols:  []string{"@@warning_count"},
+			data:  [][]driver.Value{{v}},
+			size:  1,
+			typed: s.typed,
+		}, nil
+	}
+	size := len(list)
+	if size == 0 {
+		return &Rows{}, nil
+	}
+	rs := make([][]driver.Value, size)
+	for i, w := range list {
+		code, msg := warningCode(w), w.Error()
+		rs[i] = []driver.Value{"Warning", code, msg}
+	}
+	return &Rows{
+		cols:  []string{"Level", "Code", "Message"},
+		data:  rs,
+		size:  size,
+		typed: s.typed,
+	}, nil
+}
@@ -0,0 +1,422 @@
+package driver
+
+import (
+	"strconv"
+	"strings"
+
+	parser "github.com/rvflash/awql-parser"
+)
+
+// Operators of the conditions.
+const (
+	opEqual                    = "="
+	opDifferent                = "!="
+	opSuperior                 = ">"
+	opSuperiorOrEqual          = ">="
+	opInferior                 = "<"
+	opInferiorOrEqual          = "<="
+	opIn                       = "IN"
+	opNotIn                    = "NOT_IN"
+	opStartsWith               = "STARTS_WITH"
+	opStartsWithIgnoreCase     = "STARTS_WITH_IGNORE_CASE"
+	opContains                 = "CONTAINS"
+	opContainsIgnoreCase       = "CONTAINS_IGNORE_CASE"
+	opDoesNotContain           = "DOES_NOT_CONTAIN"
+	opDoesNotContainIgnoreCase = "DOES_NOT_CONTAIN_IGNORE_CASE"
+)
+
+// newCondition returns a condition on the column.
+func newCondition(name, operator string, value []string, literal bool) parser.Condition {
+	return &parser.Where{
+		Column:         parser.NewColumn(name, ""),
+		Sign:           operator,
+		ColumnValue:    value,
+		IsValueLiteral: literal,
+	}
+}
+
+// mergeConditions returns the conditions of the statement merged with these of the view.
+// The conditions on the same column are intersected by operator: the numeric ranges are narrowed,
+// the lists of values intersected and the patterns combined.
+// It returns ErrDisjoint if the conditions are disjoint, the result set is necessarily empty.
+func mergeConditions(stmt, view []parser.Condition) ([]parser.Condition, error) {
+	// Groups the conditions by column, in order of appearance.
+	var names []string
+	cols := make(map[string][]parser.Condition)
+	for _, c := range append(append([]parser.Condition{}, stmt...), view...) {
+		if _, ok := cols[c.Name()]; !ok {
+			names = append(names, c.Name())
+		}
+		cols[c.Name()] = append(cols[c.Name()], c)
+	}
+	var where []parser.Condition
+	for _, name := range names {
+		if len(cols[name]) == 1 {
+			where = append(where, cols[name][0])
+			continue
+		}
+		conds, err := intersect(name, cols[name])
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, conds...)
+	}
+	return where, nil
+}
+
+// bound represents a numeric limit of a range.
+type bound struct {
+	c    parser.Condition
+	v    float64
+	incl bool
+}
+
+// intersect returns the conditions to use to apply all these conditions on the column.
+// It returns ErrDisjoint if no value can satisfy them.
+func intersect(name string, conds []parser.Condition) ([]parser.Condition, error) {
+	var (
+		in, out          []string
+		hasIn            bool
+		inLit, outLit    bool
+		lo, hi           *bound
+		patterns, others []parser.Condition
+	)
+	for _, c := range conds {
+		val, lit := c.Value()
+		switch c.Operator() {
+		case opEqual, opIn:
+			if !hasIn {
+				in, inLit, hasIn = val, lit, true
+			} else {
+				in = intersectValues(in, val)
+			}
+		case opDifferent, opNotIn:
+			if len(out) == 0 {
+				outLit = lit
+			}
+			out = unionValues(out, val)
+		case opSuperior, opSuperiorOrEqual, opInferior, opInferiorOrEqual:
+			f, err := strconv.ParseFloat(val[0], 64)
+			if err != nil {
+				// Not a numeric range, keeps it as it is.
+				others = append(others, c)
+				continue
+			}
+			// Keeps the narrowest bounds.
+			b := &bound{c: c, v: f, incl: strings.HasSuffix(c.Operator(), "=")}
+			switch c.Operator() {
+			case opSuperior, opSuperiorOrEqual:
+				if lo == nil || b.v > lo.v || (b.v == lo.v && !b.incl) {
+					lo = b
+				}
+			default:
+				if hi == nil || b.v < hi.v || (b.v == hi.v && !b.incl) {
+					hi = b
+				}
+			}
+		case opStartsWith, opStartsWithIgnoreCase, opContains, opContainsIgnoreCase,
+			opDoesNotContain, opDoesNotContainIgnoreCase:
+			patterns = append(patterns, c)
+		default:
+			// Operators on lists, as CONTAINS_ANY, can not be checked, keeps it as it is.
+			others = append(others, c)
+		}
+	}
+
+	if hasIn {
+		// Only keeps the values which satisfy all the other conditions.
+		var values []string
+		keepRanges := false
+		for _, v := range in {
+			if containsValue(out, v) || !matchPatterns(v, patterns) {
+				continue
+			}
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				// The value can not be checked against the numeric range.
+				keepRanges = keepRanges || lo != nil || hi != nil
+				values = append(values, v)
+				continue
+			}
+			if (lo != nil && (f < lo.v || (f == lo.v && !lo.incl))) ||
+				(hi != nil && (f > hi.v || (f == hi.v && !hi.incl))) {
+				continue
+			}
+			values = append(values, v)
+		}
+		if len(values) == 0 {
+			return nil, ErrDisjoint
+		}
+		where := []parser.Condition{newValuesCondition(name, opEqual, opIn, values, inLit)}
+		if keepRanges {
+			where = append(where, boundConditions(lo, hi)...)
+		}
+		return append(where, others...), nil
+	}
+
+	// Narrows the numeric range.
+	var where []parser.Condition
+	if lo != nil && hi != nil {
+		if lo.v > hi.v || (lo.v == hi.v && !(lo.incl && hi.incl)) {
+			return nil, ErrDisjoint
+		}
+		if lo.v == hi.v {
+			val, lit := lo.c.Value()
+			if containsValue(out, val[0]) {
+				return nil, ErrDisjoint
+			}
+			where = append(where, newCondition(name, opEqual, val, lit))
+			lo, hi = nil, nil
+		}
+	}
+	where = append(where, boundConditions(lo, hi)...)
+	if len(out) > 0 {
+		where = append(where, newValuesCondition(name, opDifferent, opNotIn, out, outLit))
+	}
+
+	// Combines the patterns.
+	patterns, err := combinePatterns(patterns)
+	if err != nil {
+		return nil, err
+	}
+	where = append(where, patterns...)
+
+	return append(where, others...), nil
+}
+
+// boundConditions returns the conditions of the numeric range.
+func boundConditions(lo, hi *bound) (where []parser.Condition) {
+	if lo != nil {
+		where = append(where, lo.c)
+	}
+	if hi != nil {
+		where = append(where, hi.c)
+	}
+	return
+}
+
+// newValuesCondition returns a condition on a single value or on a list of values.
+func newValuesCondition(name, single, list string, values []string, literal bool) parser.Condition {
+	if len(values) == 1 {
+		return newCondition(name, single, values, literal)
+	}
+	return newCondition(name, list, values, literal)
+}
+
+// combinePatterns removes the patterns implied by another one.
+// It returns ErrDisjoint if two patterns exclude each other.
+func combinePatterns(patterns []parser.Condition) ([]parser.Condition, error) {
+	drop := make([]bool, len(patterns))
+	for i, a := range patterns {
+		for j, b := range patterns {
+			if i == j {
+				continue
+			}
+			if i < j && excludes(a, b) {
+				return nil, ErrDisjoint
+			}
+			// With two identical patterns, only the last one is removed.
+			if !drop[i] && implies(a, b) && (i < j || !implies(b, a)) {
+				drop[j] = true
+			}
+		}
+	}
+	var where []parser.Condition
+	for i, c := range patterns {
+		if !drop[i] {
+			where = append(where, c)
+		}
+	}
+	return where, nil
+}
+
+// implies returns true if any value satisfying the pattern a also satisfies the pattern b.
+func implies(a, b parser.Condition) bool {
+	av, bv := patternValue(a), patternValue(b)
+	la, lb := strings.ToLower(av), strings.ToLower(bv)
+	switch b.Operator() {
+	case opStartsWith:
+		return a.Operator() == opStartsWith && strings.HasPrefix(av, bv)
+	case opStartsWithIgnoreCase:
+		switch a.Operator() {
+		case opStartsWith, opStartsWithIgnoreCase:
+			return strings.HasPrefix(la, lb)
+		}
+	case opContains:
+		switch a.Operator() {
+		case opStartsWith, opContains:
+			return strings.Contains(av, bv)
+		}
+	case opContainsIgnoreCase:
+		switch a.Operator() {
+		case opStartsWith, opStartsWithIgnoreCase, opContains, opContainsIgnoreCase:
+			return strings.Contains(la, lb)
+		}
+	case opDoesNotContain:
+		switch a.Operator() {
+		case opDoesNotContain:
+			return strings.Contains(bv, av)
+		case opDoesNotContainIgnoreCase:
+			return strings.Contains(lb, la)
+		}
+	case opDoesNotContainIgnoreCase:
+		return a.Operator() == opDoesNotContainIgnoreCase && strings.Contains(lb, la)
+	}
+	return false
+}
+
+// excludes returns true if no value can satisfy both patterns.
+func excludes(a, b parser.Condition) bool {
+	av, bv := patternValue(a), patternValue(b)
+	la, lb := strings.ToLower(av), strings.ToLower(bv)
+	switch {
+	case a.Operator() == opStartsWith && b.Operator() == opStartsWith:
+		// Two prefixes are compatible only if one is the prefix of the other.
+		return !strings.HasPrefix(av, bv) && !strings.HasPrefix(bv, av)
+	case isPrefix(a) && isPrefix(b):
+		return !strings.HasPrefix(la, lb) && !strings.HasPrefix(lb, la)
+	case isNegative(a) && !isNegative(b):
+		return excludes(b, a)
+	case !isNegative(a) && b.Operator() == opDoesNotContain:
+		// The value must contain the forbidden part.
+		switch a.Operator() {
+		case opStartsWith, opContains:
+			return strings.Contains(av, bv)
+		}
+	case !isNegative(a) && b.Operator() == opDoesNotContainIgnoreCase:
+		return strings.Contains(la, lb)
+	}
+	return false
+}
+
+// matchPatterns returns true if the value satisfies all the patterns.
+func matchPatterns(v string, patterns []parser.Condition) bool {
+	lv := strings.ToLower(v)
+	for _, c := range patterns {
+		p := patternValue(c)
+		lp := strings.ToLower(p)
+		var ok bool
+		switch c.Operator() {
+		case opStartsWith:
+			ok = strings.HasPrefix(v, p)
+		case opStartsWithIgnoreCase:
+			ok = strings.HasPrefix(lv, lp)
+		case opContains:
+			ok = strings.Contains(v, p)
+		case opContainsIgnoreCase:
+			ok = strings.Contains(lv, lp)
+		case opDoesNotContain:
+			ok = !strings.Contains(v, p)
+		case opDoesNotContainIgnoreCase:
+			ok = !strings.Contains(lv, lp)
+		default:
+			// Unknown operator, can not be checked.
+			ok = true
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isNegative returns true if the pattern excludes the values containing it.
+func isNegative(c parser.Condition) bool {
+	switch c.Operator() {
+	case opDoesNotContain, opDoesNotContainIgnoreCase:
+		return true
+	}
+	return false
+}
+
+// isPrefix returns true if the pattern is a prefix.
+func isPrefix(c parser.Condition) bool {
+	switch c.Operator() {
+	case opStartsWith, opStartsWithIgnoreCase:
+		return true
+	}
+	return false
+}
+
+// patternValue returns the value of the pattern.
+func patternValue(c parser.Condition) string {
+	if val, _ := c.Value(); len(val) > 0 {
+		return val[0]
+	}
+	return ""
+}
+
+// containsValue returns true if the value is in the list.
+func containsValue(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// intersectValues returns the values of a which are also in b.
+func intersectValues(a, b []string) (values []string) {
+	for _, v := range a {
+		if containsValue(b, v) {
+			values = append(values, v)
+		}
+	}
+	return
+}
+
+// unionValues returns the values of a with these of b not already in it.
+func unionValues(a, b []string) []string {
+	values := append([]string{}, a...)
+	for _, v := range b {
+		if !containsValue(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// matchCondition returns true if the value satisfies the condition.
+// The numeric values are compared as numbers, the others as strings.
+func matchCondition(v string, c parser.Condition) bool {
+	// compare returns -1, 0 or +1 if v is less than, equal to or greater than the value.
+	var compare = func(value string) int {
+		a, errA := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+		b, errB := strconv.ParseFloat(value, 64)
+		switch {
+		case errA != nil || errB != nil:
+			return strings.Compare(v, value)
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	}
+	// in returns true if v is one of the values.
+	var in = func(values []string) bool {
+		for _, value := range values {
+			if compare(value) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	val, _ := c.Value()
+	switch c.Operator() {
+	case opEqual, opIn:
+		return in(val)
+	case opDifferent, opNotIn:
+		return !in(val)
+	case opSuperior:
+		return compare(val[0]) > 0
+	case opSuperiorOrEqual:
+		return compare(val[0]) >= 0
+	case opInferior:
+		return compare(val[0]) < 0
+	case opInferiorOrEqual:
+		return compare(val[0]) <= 0
+	}
+	return matchPatterns(v, []parser.Condition{c})
+}
@@ -0,0 +1,191 @@
+package driver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	parser "github.com/rvflash/awql-parser"
+)
+
+// TestMergeConditions tests the function named mergeConditions.
+func TestMergeConditions(t *testing.T) {
+	// Builds a condition on the column with the operator and the values.
+	var cond = func(name, op string, values ...string) parser.Condition {
+		return newCondition(name, op, values, false)
+	}
+	// Returns the conditions as strings, like "Clicks > 10".
+	var format = func(conds []parser.Condition) (list []string) {
+		for _, c := range conds {
+			val, _ := c.Value()
+			list = append(list, c.Name()+" "+c.Operator()+" "+strings.Join(val, ","))
+		}
+		return
+	}
+	var whereTests = []struct {
+		stmt, view []parser.Condition
+		out        []string
+		err        error
+	}{
+		// Without conditions to merge.
+		{},
+		{
+			stmt: []parser.Condition{cond("Clicks", opSuperior, "0")},
+			view: []parser.Condition{cond("Impressions", opSuperior, "10")},
+			out:  []string{"Clicks > 0", "Impressions > 10"},
+		},
+		// Numeric ranges.
+		{
+			stmt: []parser.Condition{cond("Clicks", opSuperior, "0")},
+			view: []parser.Condition{cond("Clicks", opSuperior, "1000")},
+			out:  []string{"Clicks > 1000"},
+		},
+		{
+			stmt: []parser.Condition{cond("Clicks", opSuperiorOrEqual, "10")},
+			view: []parser.Condition{cond("Clicks", opSuperior, "10")},
+			out:  []string{"Clicks > 10"},
+		},
+		{
+			stmt: []parser.Condition{cond("Clicks", opSuperior, "0"), cond("Clicks", opInferior, "100")},
+			view: []parser.Condition{cond("Clicks", opInferiorOrEqual, "50")},
+			out:  []string{"Clicks > 0", "Clicks <= 50"},
+		},
+		{
+			stmt: []parser.Condition{cond("Clicks", opSuperiorOrEqual, "10")},
+			view: []parser.Condition{cond("Clicks", opInferiorOrEqual, "10")},
+			out:  []string{"Clicks = 10"},
+		},
+		{
+			stmt: []parser.Condition{cond("Clicks", opSuperiorOrEqual, "10"), cond("Clicks", opDifferent, "5")},
+			view: []parser.Condition{cond("Clicks", opInferiorOrEqual, "10")},
+			out:  []string{"Clicks = 10", "Clicks != 5"},
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignName", opSuperior, "a")},
+			view: []parser.Condition{cond("CampaignName", opInferior, "b")},
+			out:  []string{"CampaignName > a", "CampaignName < b"},
+		},
+		// Lists of values.
+		{
+			stmt: []parser.Condition{cond("CampaignId", opIn, "1", "2", "3")},
+			view: []parser.Condition{cond("CampaignId", opEqual, "2")},
+			out:  []string{"CampaignId = 2"},
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignId", opIn, "1", "2", "3")},
+			view: []parser.Condition{cond("CampaignId", opIn, "2", "3", "4")},
+			out:  []string{"CampaignId IN 2,3"},
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignId", opIn, "1", "2", "3")},
+			view: []parser.Condition{cond("CampaignId", opNotIn, "2")},
+			out:  []string{"CampaignId IN 1,3"},
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignId", opIn, "1", "2", "3")},
+			view: []parser.Condition{cond("CampaignId", opSuperior, "1")},
+			out:  []string{"CampaignId IN 2,3"},
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignStatus", opDifferent, "REMOVED")},
+			view: []parser.Condition{cond("CampaignStatus", opDifferent, "PAUSED")},
+			out:  []string{"CampaignStatus NOT_IN REMOVED,PAUSED"},
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignName", opIn, "ab", "cd")},
+			view: []parser.Condition{cond("CampaignName", opStartsWith, "a")},
+			out:  []string{"CampaignName = ab"},
+		},
+		// Patterns.
+		{
+			stmt: []parser.Condition{cond("CampaignName", opStartsWith, "ab")},
+			view: []parser.Condition{cond("CampaignName", opContains, "a")},
+			out:  []string{"CampaignName STARTS_WITH ab"},
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignName", opContainsIgnoreCase, "AB")},
+			view: []parser.Condition{cond("CampaignName", opDoesNotContain, "z")},
+			out:  []string{"CampaignName CONTAINS_IGNORE_CASE AB", "CampaignName DOES_NOT_CONTAIN z"},
+		},
+		// Operators on lists, kept as they are.
+		{
+			stmt: []parser.Condition{cond("Labels", opIn, "a", "b")},
+			view: []parser.Condition{cond("Labels", "CONTAINS_ANY", "a", "c")},
+			out:  []string{"Labels IN a,b", "Labels CONTAINS_ANY a,c"},
+		},
+		{
+			stmt: []parser.Condition{cond("Labels", "CONTAINS_ALL", "a")},
+			view: []parser.Condition{cond("Labels", "CONTAINS_NONE", "b"), cond("Labels", opStartsWith, "a")},
+			out:  []string{"Labels STARTS_WITH a", "Labels CONTAINS_ALL a", "Labels CONTAINS_NONE b"},
+		},
+		// Disjoint conditions.
+		{
+			stmt: []parser.Condition{cond("Clicks", opSuperior, "10")},
+			view: []parser.Condition{cond("Clicks", opInferior, "5")},
+			err:  ErrDisjoint,
+		},
+		{
+			stmt: []parser.Condition{cond("Clicks", opSuperior, "10")},
+			view: []parser.Condition{cond("Clicks", opInferiorOrEqual, "10")},
+			err:  ErrDisjoint,
+		},
+		{
+			stmt: []parser.Condition{cond("Clicks", opSuperiorOrEqual, "10"), cond("Clicks", opDifferent, "10")},
+			view: []parser.Condition{cond("Clicks", opInferiorOrEqual, "10")},
+			err:  ErrDisjoint,
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignId", opIn, "1", "2")},
+			view: []parser.Condition{cond("CampaignId", opEqual, "3")},
+			err:  ErrDisjoint,
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignId", opEqual, "1")},
+			view: []parser.Condition{cond("CampaignId", opNotIn, "1", "2")},
+			err:  ErrDisjoint,
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignName", opStartsWith, "ab")},
+			view: []parser.Condition{cond("CampaignName", opStartsWith, "cd")},
+			err:  ErrDisjoint,
+		},
+		{
+			stmt: []parser.Condition{cond("CampaignName", opContains, "foo")},
+			view: []parser.Condition{cond("CampaignName", opDoesNotContain, "o")},
+			err:  ErrDisjoint,
+		},
+	}
+	for i, tt := range whereTests {
+		where, err := mergeConditions(tt.stmt, tt.view)
+		if err != tt.err {
+			t.Errorf("%d. Expected error %v, received %v", i, tt.err, err)
+		} else if out := format(where); !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("%d. Expected %q, received %q", i, tt.out, out)
+		}
+	}
+}
+
+// TestMatchCondition tests the function named matchCondition.
+func TestMatchCondition(t *testing.T) {
+	var matchTests = []struct {
+		v  string
+		c  parser.Condition
+		ok bool
+	}{
+		{v: "10", c: newCondition("Clicks", opSuperior, []string{"9.5"}, false), ok: true},
+		{v: "10", c: newCondition("Clicks", opSuperior, []string{"10"}, false)},
+		{v: "10", c: newCondition("Clicks", opSuperiorOrEqual, []string{"10"}, false), ok: true},
+		{v: "9", c: newCondition("Clicks", opInferior, []string{"10"}, false), ok: true},
+		{v: "12.50%", c: newCondition("Ctr", opInferiorOrEqual, []string{"12.5"}, false), ok: true},
+		{v: "2", c: newCondition("CampaignId", opIn, []string{"1", "2"}, false), ok: true},
+		{v: "3", c: newCondition("CampaignId", opNotIn, []string{"1", "2"}, false), ok: true},
+		{v: "2", c: newCondition("CampaignId", opDifferent, []string{"2"}, false)},
+		{v: "Campaign", c: newCondition("CampaignName", opStartsWithIgnoreCase, []string{"camp"}, true), ok: true},
+		{v: "Campaign", c: newCondition("CampaignName", opDoesNotContain, []string{"pai"}, true)},
+	}
+	for i, tt := range matchTests {
+		if ok := matchCondition(tt.v, tt.c); ok != tt.ok {
+			t.Errorf("%d. Expected %v, received %v", i, tt.ok, ok)
+		}
+	}
+}
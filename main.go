@@ -6,8 +6,11 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/rvflash/awql/conf"
+	"github.com/rvflash/awql/docs"
 	"github.com/rvflash/awql/ui"
 )
 
@@ -16,17 +19,45 @@ import (
 // Usage of awql:
 // 	-A	Disables automatic rehashing
 // 	-B	Enables printing of results using comma as the column separator
+// 	-C string
+// 		Path to a read-only catalog of views, shared by a team (default $AWQL_CATALOG)
 // 	-D string
 // 		Google OAuth developer token
+// 	-S string
+// 		Path to a directory of API schemas, with a reports.yml file by version as v201809/reports.yml (default $AWQL_SCHEMA or ~/.awql/schema)
 // 	-T string
 // 		Google OAuth access token
 // 	-V string
 // 		Google Adwords API version (default "v201809")
+// 	-ads
+// 		Sends the queries to the Google Ads API, translated in GAQL, instead of the Adwords reports
+// 	-ads-endpoint string
+// 		Base URL of the Google Ads API
+// 	-ads-version string
+// 		Google Ads API version (default "v17")
 // 	-c	Enables data caching
+// 	-check-schema string
+// 		Checks the structure of a reports.yml schema file, then exits
+// 	-check-views
+// 		Checks the views against the API version given with -V, then exits
+// 	-docs string
+// 		Generates in this directory the HTML and Markdown documentation of the reports and views of the API version, then exits
 // 	-e string
 // 		Execute AWQL statement, disables interactive use
 // 	-i string
 // 		Google Adwords account ID
+// 	-login-customer-id string
+// 		Google Ads manager account ID to use as login customer
+// 	-raw-enums
+// 		Enables the enum values as defined in the API, as ENABLED instead of enabled
+// 	-rename-columns string
+// 		Path to a Yaml file of the columns to rename in the views, as OldName: NewName, before checking them
+// 	-schema-changes string
+// 		Lists the changes of the reports from this API version to the one given with -V, then exits
+// 	-summary
+// 		Enables the display of the totals computed by Adwords after the records
+// 	-table string
+// 		Only lists the changes of this table with -schema-changes
 // 	-v	Enables verbose mode
 // 	-z	Enables fetching of reports with the support of zero impressions
 //
@@ -38,9 +69,68 @@ func main() {
 	}
 	// Initializes all environment properties.
 	conf := conf.New(filepath.Dir(file))
+	if f := conf.SchemaFile(); f != "" {
+		// Only checks the schema file, without loading the schemas it may fix.
+		if err := conf.CheckSchema(); err != nil {
+			exit(err)
+		}
+		fmt.Println(f + ": OK")
+		exit(nil)
+	}
 	if err := conf.Init(); err != nil {
 		exit(err)
 	}
+	if conf.ChangesVersion() != "" {
+		// Only lists the changes between two versions of the API.
+		changes, err := conf.SchemaChanges()
+		if err != nil {
+			exit(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Table\tColumn\tChange\tDetail")
+		for _, c := range changes {
+			fmt.Fprintln(w, strings.Join([]string{c.Table, c.Column, c.Kind, c.Detail}, "\t"))
+		}
+		exit(w.Flush())
+	}
+	if conf.WithViewsCheck() || conf.RenameColumnsFile() != "" {
+		// Only checks the views against a version of the API.
+		n, issues, err := conf.CheckViews()
+		if err != nil {
+			exit(err)
+		}
+		if n > 0 {
+			fmt.Printf("%d view(s) rewritten\n", n)
+		}
+		if len(issues) == 0 {
+			fmt.Println(conf.ViewsFile() + ": OK (" + conf.APIVersion() + ")")
+			exit(nil)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "View\tColumn\tProblem\tDetail")
+		for _, c := range issues {
+			fmt.Fprintln(w, strings.Join([]string{c.View, c.Column, c.Kind, c.Detail}, "\t"))
+		}
+		if err := w.Flush(); err != nil {
+			exit(err)
+		}
+		exit(fmt.Errorf("%d problem(s) found in the views", len(issues)))
+	}
+	if dir := conf.DocsDir(); dir != "" {
+		// Only generates the documentation of the schema.
+		d, err := conf.Database()
+		if err != nil {
+			exit(err)
+		}
+		for _, w := range d.Warnings() {
+			fmt.Println("Warning: " + w.Error())
+		}
+		if err := docs.Generate(d, dir); err != nil {
+			exit(err)
+		}
+		fmt.Println(filepath.Join(dir, d.Version, docs.IndexFile))
+		exit(nil)
+	}
 	// Launch the environment.
 	var src ui.Scanner
 	if conf.IsInteractive() {
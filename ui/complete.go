@@ -39,8 +39,11 @@ func (c *completer) Do(line []rune, pos int) (newLine [][]rune, length int) {
 	if s := buf.String(); len(s) < l {
 		// Expected: `METHOD `
 		switch strings.ToUpper(s) {
-		case "CREATE":
+		case "CREATE", "ALTER", "EXPLAIN":
+			// Completes the select statement.
 			return c.createCompleter(line, pos)
+		case "DROP", "RENAME", "REFRESH":
+			return c.viewCompleter(line, pos)
 		case "DESC", "DESCRIBE":
 			return c.describeCompleter(line, pos)
 		case "SELECT":
@@ -71,6 +74,30 @@ func (c *completer) createCompleter(line []rune, pos int) ([][]rune, int) {
 	return nil, 0
 }
 
+// viewCompleter
+func (c *completer) viewCompleter(line []rune, pos int) ([][]rune, int) {
+	t := tokenize(string(line[:pos]))
+	l := len(t)
+	tpos := 1
+	if l > 1 && strings.EqualFold("MATERIALIZED", t[tpos]) {
+		tpos++
+	}
+	if l < tpos+2 || !strings.EqualFold("VIEW", t[tpos]) {
+		// Expected: `[DROP VIEW ]` or `[REFRESH MATERIALIZED VIEW ]`
+		return nil, 0
+	}
+	// Searches the position of the view name.
+	tpos++
+	if strings.EqualFold("IF", t[tpos]) && l > 3 && strings.EqualFold("EXISTS", t[tpos+1]) {
+		tpos += 2
+	}
+	if tpos != l-1 {
+		return nil, 0
+	}
+	// Lists the view names.
+	return candidates(c.listViews(t[tpos]), len(t[tpos]))
+}
+
 // describeCompleter
 func (c *completer) describeCompleter(line []rune, pos int) ([][]rune, int) {
 	t := tokenize(string(line[:pos]))
@@ -293,6 +320,26 @@ func (c *completer) showCompleter(line []rune, pos int) ([][]rune, int) {
 		// Expected: `[SHOW TABLES WITH ]`
 		return nil, 0
 	}
+	// Searches the table name of a SHOW COLUMNS statement.
+	for i := 2; i < l-1; i++ {
+		if !strings.EqualFold("FROM", t[i]) {
+			continue
+		}
+		if i == l-2 {
+			// Expected: `[SHOW COLUMNS FROM CAMP]`
+			return candidates(c.listTables(t[l-1]), len(t[l-1]))
+		}
+		if l-i < 4 || !strings.EqualFold("FOR", t[i+2]) {
+			return nil, 0
+		}
+		// Expected: `[SHOW COMPATIBLE COLUMNS FROM CAMPAIGN_PERFORMANCE_REPORT FOR ( CampaignName, Ho]`
+		tb, err := c.db.Table(t[i+1])
+		if err != nil {
+			return nil, 0
+		}
+		s := t[l-1][strings.LastIndex(t[l-1], ",")+1:]
+		return candidates(c.listTableColumns(tb, s), len(s))
+	}
 	// Searches the position of the column name.
 	cpos := 1
 	if strings.EqualFold("FULL", t[cpos]) {
@@ -321,7 +368,12 @@ func (c *completer) listTableColumns(tb db.DataTable, prefix string) (names []st
 	}
 	names = make([]string, len(columns))
 	for i, c := range columns {
-		names[i] = c.Name()
+		if tb.IsView() && c.Alias() != "" {
+			// In view, the columns are known by their alias.
+			names[i] = c.Alias()
+		} else {
+			names[i] = c.Name()
+		}
 	}
 	return
 }
@@ -346,6 +398,16 @@ func (c *completer) listTables(prefix string) (names []string) {
 	return
 }
 
+// listViews returns the names of the views prefixed by this pattern.
+func (c *completer) listViews(prefix string) (names []string) {
+	for _, t := range c.db.TablesPrefixedBy(prefix) {
+		if t.IsView() {
+			names = append(names, t.SourceName())
+		}
+	}
+	return
+}
+
 // tokenize returns a slice of string by splitting it by space.
 func tokenize(s string) []string {
 	// Also manages `(` as separator to manage the methods.
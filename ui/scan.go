@@ -1,13 +1,14 @@
 package ui
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/gohxs/readline"
-	db "github.com/rvflash/awql-db"
 	awql "github.com/rvflash/awql-driver"
 	parser "github.com/rvflash/awql-parser"
 	"github.com/rvflash/awql/conf"
@@ -16,16 +17,31 @@ import (
 
 // Shell commands and prompts.
 const (
-	// Prompts
-	prompt          = "awql> "
+	// Prompts, the first one with the account in use.
+	promptFormat    = "awql [%s]> "
 	promptMultiLine = "   -> "
 
+	// Label of the report summary, used when its first column has no value.
+	// Google uses ` --` instead of an empty string to symbolize the fact that the field was never set.
+	summaryLabel = "Total"
+	doubleDash   = " --"
+
 	// Commands
 	shortCmdClear = "c"
 	shortCmdHelp  = "h"
 	shortCmdExit  = "q"
 )
 
+// Output formats of the result sets, changed with the output variable.
+const (
+	varOutput = "output"
+
+	outputTable    = "table"
+	outputVertical = "vertical"
+	outputCSV      = "csv"
+	outputJSON     = "json"
+)
+
 // Command represents a command.
 type Command struct {
 	text, cmd, usage string
@@ -77,9 +93,14 @@ type ScanSeeker interface {
 }
 
 // CommandLine represents a basic input.
+// All the statements are sent on the same connection, the warnings and the session being these of a connection.
+// The SET statements changing the session of the driver are kept to be replayed on reconnection.
 type CommandLine struct {
-	c conf.Settings
-	d *sql.DB
+	c    conf.Settings
+	d    *sql.DB
+	cn   *sql.Conn
+	out  string
+	sets map[string]string
 }
 
 // NewCommandLine returns a basic input.
@@ -90,10 +111,11 @@ func NewCommandLine(conf conf.Settings) ScanSeeker {
 // Scan starts the engine with only the query query to execute from args.
 func (e *CommandLine) Scan() (err error) {
 	// Opens the Awql connection.
-	e.d, err = sql.Open("aawql", e.c.Dsn())
+	e.d, e.cn, err = e.connect()
 	if err != nil {
 		return
 	}
+	defer e.close()
 	// Sends statement to Advanced Awql driver.
 	return e.Seek(e.c.ExecuteStmt())
 }
@@ -107,83 +129,330 @@ func (e *CommandLine) Seek(s string) error {
 	}
 	for _, stmt := range stmts {
 		var w Writer
-		if _, ok := stmt.(parser.CreateViewStmt); ok {
+		if isExec(stmt) {
 			// Use a basic writer, just to aggregate statistics.
 			w = NewStatsWriter(os.Stdout, true)
 
-			// Sends the query.
-			if _, err = e.d.Exec(stmt.String()); err != nil {
+			// Sends the query, the variables of the user interface and the account are managed locally.
+			switch st := stmt.(type) {
+			case parser.SetStmt:
+				err = e.set(st)
+			case parser.UseStmt:
+				err = e.use(st)
+			default:
+				_, err = e.cn.ExecContext(context.Background(), stmt.String())
+			}
+			if err != nil {
 				fmt.Println(err)
 				continue
 			}
+			e.warn(w)
 			w.Flush()
 		} else {
 			// Chooses the table writer.
-			switch {
-			case e.c.UseBatchMode():
+			switch out := e.output(); {
+			case out == outputCSV:
 				w = NewCsvWriter(os.Stdout)
-			case stmt.VerticalOutput():
+			case out == outputJSON:
+				w = NewJSONWriter(os.Stdout)
+			case out == outputVertical, stmt.VerticalOutput():
 				w = NewVASCIIWriter(os.Stdout)
 			default:
 				w = NewASCIIWriter(os.Stdout)
 			}
 
+			// The variables of the user interface are listed with these of the driver,
+			// the accounts are these of the settings.
+			var local func() error
+			switch st := stmt.(type) {
+			case *parser.ShowVariablesStatement:
+				local = func() error { return e.variables(w, st) }
+			case *parser.ShowAccountsStatement:
+				local = func() error { return e.accounts(w, st) }
+			}
+			if local != nil {
+				if err := local(); err != nil {
+					fmt.Println(err)
+					continue
+				}
+				e.warn(w)
+				w.Flush()
+				continue
+			}
+
 			// Sends the query.
-			rs, err := e.d.Query(stmt.String())
+			rs, err := e.cn.QueryContext(context.Background(), stmt.String())
 			if err != nil {
 				fmt.Println(err)
 				continue
 			}
 
-			// Get the column names.
-			cols, _ := rs.Columns()
-			if len(cols) == 0 {
-				// No data set.
-				w.Flush()
-				continue
-			} else if err := w.WriteHead(cols); err != nil {
-				// Unable to write header.
+			if err := e.rows(w, rs); err != nil {
 				return err
 			}
 
-			// Create slices to manage the rows.
-			size := len(cols)
-			vals := make([]string, size)
-			ints := make([]interface{}, size)
-			for i := range ints {
-				ints[i] = &vals[i]
-			}
-			for rs.Next() {
-				rs.Scan(ints...)
-				if err := w.Write(vals); err != nil {
-					return err
-				}
-			}
-
 			// Write any buffered data and statistics.
+			e.warn(w)
 			w.Flush()
 
 			if err := w.Error(); err != nil {
 				return err
 			}
+		}
+	}
 
-			if err := rs.Err(); err != nil {
+	return nil
+}
+
+// rows writes the columns and the records of the result set, then closes it to release the connection.
+func (e *CommandLine) rows(w Writer, rs *sql.Rows) error {
+	defer rs.Close()
+
+	// Get the column names.
+	cols, _ := rs.Columns()
+	if len(cols) == 0 {
+		// No data set.
+		return nil
+	}
+
+	// Create slices to manage the rows.
+	// The shell uses the values as Google outputs them, as strings.
+	size := len(cols)
+	vals := make([]string, size)
+	ints := make([]interface{}, size)
+	for i := range ints {
+		ints[i] = &vals[i]
+	}
+	for head := false; rs.Next(); {
+		if !head {
+			// The header is written with the first record, an empty set only has statistics.
+			if err := w.WriteHead(cols); err != nil {
+				// Unable to write header.
 				return err
 			}
+			head = true
+		}
+		if err := rs.Scan(ints...); err != nil {
+			return err
+		}
+		if err := w.Write(vals); err != nil {
+			return err
 		}
 	}
+	// The report summary follows the records, as footer if the writer supports it.
+	if rs.NextResultSet() && rs.Next() {
+		if err := rs.Scan(ints...); err != nil {
+			return err
+		}
+		if err := e.total(w, vals); err != nil {
+			return err
+		}
+	}
+	return rs.Err()
+}
+
+// output returns the format of the result sets.
+// By default, the batch mode uses the CSV format.
+func (e *CommandLine) output() string {
+	switch {
+	case e.out != "":
+		return e.out
+	case e.c.UseBatchMode():
+		return outputCSV
+	}
+	return outputTable
+}
 
+// set changes the value of the variable.
+// The output format is a setting of the user interface, a new connection is opened with the API version
+// or the cache of the settings. The other variables are sent to the session of the driver.
+func (e *CommandLine) set(stmt parser.SetStmt) error {
+	v, _ := stmt.Value()
+	switch strings.ToLower(stmt.Name()) {
+	case varOutput:
+		switch out := strings.ToLower(v); out {
+		case outputTable, outputVertical, outputCSV, outputJSON:
+			e.out = out
+			return nil
+		}
+		return driver.NewXError("invalid value", stmt.Name()+" = "+v)
+	case driver.VarAPIVersion:
+		version := e.c.APIVersion()
+		if err := e.c.SetAPIVersion(v); err != nil {
+			return err
+		}
+		if err := e.reconnect(); err != nil {
+			// The connection in use is kept, as its version.
+			if rerr := e.c.SetAPIVersion(version); rerr != nil {
+				fmt.Println(rerr)
+			}
+			return err
+		}
+		return nil
+	case driver.VarCache:
+		on, ok := driver.ParseSwitch(v)
+		if !ok {
+			return driver.NewXError("invalid value", stmt.Name()+" = "+v)
+		}
+		cache := e.c.WithCache()
+		e.c.SetCache(on)
+		if err := e.reconnect(); err != nil {
+			e.c.SetCache(cache)
+			return err
+		}
+		return nil
+	}
+	if _, err := e.cn.ExecContext(context.Background(), stmt.String()); err != nil {
+		return err
+	}
+	if e.sets == nil {
+		e.sets = make(map[string]string)
+	}
+	e.sets[stmt.Name()] = stmt.String()
+
+	return nil
+}
+
+// use changes the account, given by its ID or its alias, and opens a new connection for it.
+func (e *CommandLine) use(stmt parser.UseStmt) error {
+	id := e.c.AccountID()
+	if err := e.c.SetAccount(stmt.Account()); err != nil {
+		return err
+	}
+	if err := e.reconnect(); err != nil {
+		// The connection in use is kept, as its account.
+		if rerr := e.c.SetAccount(id); rerr != nil {
+			fmt.Println(rerr)
+		}
+		return err
+	}
 	return nil
 }
 
+// connect opens the database with the current settings and returns the connection used by the statements.
+func (e *CommandLine) connect() (*sql.DB, *sql.Conn, error) {
+	d, err := sql.Open("aawql", e.c.Dsn())
+	if err != nil {
+		return nil, nil, err
+	}
+	cn, err := d.Conn(context.Background())
+	if err != nil {
+		d.Close()
+		return nil, nil, err
+	}
+	return d, cn, nil
+}
+
+// close closes the connection and its database.
+func (e *CommandLine) close() {
+	if e.cn != nil {
+		e.cn.Close()
+	}
+	if e.d != nil {
+		e.d.Close()
+	}
+}
+
+// reconnect opens a new connection with the current settings and closes the previous one.
+// The session of the new connection is restored with the SET statements already sent to the driver.
+func (e *CommandLine) reconnect() error {
+	d, cn, err := e.connect()
+	if err != nil {
+		return err
+	}
+	for _, q := range e.sets {
+		if _, err := cn.ExecContext(context.Background(), q); err != nil {
+			cn.Close()
+			d.Close()
+			return err
+		}
+	}
+	e.close()
+	e.d, e.cn = d, cn
+
+	return nil
+}
+
+// variables writes the variables of the session of the driver with these of the user interface,
+// sorted by name and filtered by the like clause.
+func (e *CommandLine) variables(w Writer, stmt parser.ShowVariablesStmt) error {
+	rs, err := e.cn.QueryContext(context.Background(), stmt.String())
+	if err != nil {
+		return err
+	}
+	defer rs.Close()
+
+	var vars [][]string
+	for rs.Next() {
+		var name, value string
+		if err := rs.Scan(&name, &value); err != nil {
+			return err
+		}
+		vars = append(vars, []string{name, value})
+	}
+	if err := rs.Err(); err != nil {
+		return err
+	}
+	if p, ok := stmt.LikePattern(); !ok || p.Match(varOutput) {
+		vars = append(vars, []string{varOutput, e.output()})
+	}
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i][0] < vars[j][0]
+	})
+	return writeRecords(w, []string{"Variable_name", "Value"}, vars)
+}
+
+// accounts writes the accounts of the settings with their alias, filtered by the like clause on both.
+func (e *CommandLine) accounts(w Writer, stmt parser.ShowAccountsStmt) error {
+	list, err := e.c.Accounts()
+	if err != nil {
+		return err
+	}
+	p, like := stmt.LikePattern()
+	var accounts [][]string
+	for _, a := range list {
+		if like && !p.Match(a.ID) && !p.Match(a.Alias) {
+			continue
+		}
+		accounts = append(accounts, []string{a.ID, a.Alias})
+	}
+	return writeRecords(w, []string{"Account_id", "Alias"}, accounts)
+}
+
+// total writes the report summary, with its label in the first column if this one has no value.
+func (e *CommandLine) total(w Writer, record []string) error {
+	if len(record) > 0 && record[0] == doubleDash {
+		record[0] = summaryLabel
+	}
+	if fw, ok := w.(Footer); ok {
+		return fw.WriteFooter(record)
+	}
+	return w.Write(record)
+}
+
+// warn gives to the writer the number of warnings raised by the last statement.
+func (e *CommandLine) warn(w Writer) {
+	ww, ok := w.(Warner)
+	if !ok {
+		// The writer ignores the warnings.
+		return
+	}
+	var n int
+	if err := e.cn.QueryRowContext(context.Background(), "SHOW COUNT(*) WARNINGS").Scan(&n); err == nil {
+		ww.Warn(n)
+	}
+}
+
 // Terminal represents a terminal as stdin (shell).
+// Its prompt shows the account in use.
 type Terminal struct {
 	CommandLine
+	ps string
 }
 
 // NewTerminal returns an instance of Terminal.
 func NewTerminal(conf conf.Settings) ScanSeeker {
-	return &Terminal{CommandLine{c: conf}}
+	return &Terminal{CommandLine: CommandLine{c: conf}}
 }
 
 // Scan starts the engine with a listening of the terminal as stdin.
@@ -191,8 +460,9 @@ func (e *Terminal) Scan() error {
 	// Prints to standard output the welcome message.
 	e.printWelcome()
 
+	e.ps = e.prompt()
 	rc := &readline.Config{
-		Prompt:                 prompt,
+		Prompt:                 e.ps,
 		HistoryFile:            e.c.HistoryFile(),
 		DisableAutoSaveHistory: true,
 		InterruptPrompt:        "^C", // CTRL + C
@@ -214,10 +484,12 @@ func (e *Terminal) Scan() error {
 	defer reader.Close()
 
 	// Establishes the connection.
-	e.d, err = sql.Open("aawql", e.c.Dsn())
+	e.d, e.cn, err = e.connect()
 	if err != nil {
 		return err
 	}
+	defer e.close()
+	e.printWarnings()
 
 	// Listens and reads statements from the shell.
 	for {
@@ -247,6 +519,7 @@ func (e *Terminal) Scan() error {
 		}
 
 		// Sends statement to Advanced Awql driver.
+		account, version := e.c.AccountID(), e.c.APIVersion()
 		if err := e.Seek(q); err != nil {
 			switch err.(type) {
 			case
@@ -257,6 +530,21 @@ func (e *Terminal) Scan() error {
 				return err
 			}
 		}
+		// Reloads the auto-completion with the tables of the new API version.
+		if e.c.WithAutoRehash() && e.c.APIVersion() != version {
+			if ac, err := e.completer(); err == nil {
+				rc.AutoComplete = ac
+			}
+		}
+		// Uses the prompt and the history of the new account.
+		// A new configuration is required to open its history file.
+		if e.c.AccountID() != account {
+			e.ps = e.prompt()
+			rc = rc.Clone()
+			rc.Prompt = e.ps
+			rc.HistoryFile = e.c.HistoryFile()
+			reader.SetConfig(rc)
+		}
 	}
 
 	return nil
@@ -264,13 +552,27 @@ func (e *Terminal) Scan() error {
 
 // completer returns if possible the auto-completion to offer.
 func (e *Terminal) completer() (readline.AutoCompleter, error) {
-	lx, err := db.Open(e.c.APIVersion() + "|" + e.c.DatabaseDir())
+	lx, err := e.c.Database()
 	if err != nil {
 		return nil, err
 	}
 	return &completer{lx}, nil
 }
 
+// prompt returns the prompt with the alias of the account in use, or its ID without alias.
+func (e *Terminal) prompt() string {
+	name := e.c.AccountID()
+	if list, err := e.c.Accounts(); err == nil {
+		for _, a := range list {
+			if a.ID == name && a.Alias != "" {
+				name = a.Alias
+				break
+			}
+		}
+	}
+	return fmt.Sprintf(promptFormat, name)
+}
+
 // printAborted writes the unhappy end message.
 func (e *Terminal) printAborted() {
 	fmt.Println("Aborted")
@@ -315,6 +617,29 @@ func (e *Terminal) printWelcome() {
 	fmt.Println("")
 }
 
+// printWarnings writes to standard output the warnings raised by the loading of the database,
+// as the views skipped because of an unknown column.
+func (e *Terminal) printWarnings() {
+	rs, err := e.cn.QueryContext(context.Background(), "SHOW WARNINGS")
+	if err != nil {
+		return
+	}
+	defer rs.Close()
+
+	var n int
+	for rs.Next() {
+		var level, code, msg string
+		if err := rs.Scan(&level, &code, &msg); err != nil {
+			return
+		}
+		fmt.Printf("%s (Code %s): %s\n", strings.TrimSpace(level), strings.TrimSpace(code), strings.TrimSpace(msg))
+		n++
+	}
+	if n > 0 {
+		fmt.Println("")
+	}
+}
+
 // readLine returns the last statement or an error.
 func (e *Terminal) readLine(reader *readline.Instance) (string, error) {
 	var lines []string
@@ -337,7 +662,7 @@ func (e *Terminal) readLine(reader *readline.Instance) (string, error) {
 		}
 
 		// Resets the environment for the next read line.
-		reader.SetPrompt(prompt)
+		reader.SetPrompt(e.ps)
 
 		// Computes all lines in one.
 		q := strings.Join(lines, " ")
@@ -350,6 +675,33 @@ func (e *Terminal) readLine(reader *readline.Instance) (string, error) {
 	}
 }
 
+// writeRecords writes the header and the records, nothing without record.
+func writeRecords(w Writer, head []string, records [][]string) error {
+	if len(records) == 0 {
+		return nil
+	}
+	if err := w.WriteHead(head); err != nil {
+		return err
+	}
+	for _, r := range records {
+		if err := w.Write(r); err != nil {
+			return err
+		}
+	}
+	return w.Error()
+}
+
+// isExec returns true if the statement does not return rows.
+func isExec(stmt parser.Stmt) bool {
+	switch stmt.(type) {
+	case
+		parser.CreateViewStmt, parser.DropViewStmt, parser.RenameViewStmt,
+		*parser.RefreshViewStatement, parser.SetStmt, parser.UseStmt:
+		return true
+	}
+	return false
+}
+
 // withStmtEnd returns true if the statement ends with ";" or "\G".
 func withStmtEnd(q string) bool {
 	switch {
@@ -3,6 +3,7 @@ package ui
 import (
 	"bufio"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
@@ -31,9 +32,20 @@ type Positioner interface {
 	Position() int
 }
 
+// Warner is an interface used by Warn.
+type Warner interface {
+	Warn(n int)
+}
+
+// Footer is an interface used by WriteFooter.
+type Footer interface {
+	WriteFooter(record []string) error
+}
+
 // PositionWriter represents a writer using a positioner.
 type PositionWriter interface {
 	Positioner
+	Warner
 	Writer
 }
 
@@ -67,11 +79,80 @@ func (w *CsvWriter) WriteHead(record []string) error {
 	return w.Write(record)
 }
 
+// JSONWriter is a writer that builds a JSON array of objects, one by record.
+// Each value is stored under the name of its column.
+type JSONWriter struct {
+	w    *bufio.Writer
+	head []string
+	size int
+	err  error
+}
+
+// NewJSONWriter returns a JSON writer.
+func NewJSONWriter(w io.Writer) Writer {
+	return &JSONWriter{w: bufio.NewWriter(w)}
+}
+
+// Error returns the error occurred during the writing.
+func (w *JSONWriter) Error() error {
+	return w.err
+}
+
+// Flush ends the JSON array and writes any buffered data to the underlying writer.
+func (w *JSONWriter) Flush() {
+	if w.head != nil {
+		if w.size > 0 {
+			w.w.WriteString("\n")
+		}
+		w.w.WriteString("]\n")
+	}
+	if err := w.w.Flush(); err != nil && w.err == nil {
+		w.err = err
+	}
+}
+
+// Write writes the record as a JSON object.
+func (w *JSONWriter) Write(record []string) error {
+	sep := ",\n"
+	if w.size == 0 {
+		sep = "\n"
+	}
+	w.size++
+
+	obj := make([]string, len(record))
+	for i, v := range record {
+		var name string
+		if i < len(w.head) {
+			name = w.head[i]
+		}
+		k, _ := json.Marshal(name)
+		b, _ := json.Marshal(v)
+		obj[i] = string(k) + ":" + string(b)
+	}
+	if _, err := w.w.WriteString(sep + "{" + strings.Join(obj, ",") + "}"); err != nil {
+		w.err = err
+	}
+	return w.err
+}
+
+// WriteHead defines the column names used as keys of the objects and starts the JSON array.
+func (w *JSONWriter) WriteHead(record []string) error {
+	w.head = make([]string, len(record))
+	for i, v := range record {
+		w.head[i] = strings.TrimSpace(v)
+	}
+	if _, err := w.w.WriteString("["); err != nil {
+		w.err = err
+	}
+	return w.err
+}
+
 // StatsWriter represents a statistics's writer.
 type StatsWriter struct {
 	w        *bufio.Writer
 	affected bool
 	size     int
+	warnings int
 	t0, t1   time.Time
 }
 
@@ -97,12 +178,20 @@ func (w *StatsWriter) Flush() {
 		// Statement using exec mode.
 		w.t1 = time.Now()
 	}
-	// Outputs statistics about the result set.
+	// Outputs statistics about the result set, with the number of warnings if any.
+	var warnings string
+	switch w.warnings {
+	case 0:
+	case 1:
+		warnings = ", 1 warning"
+	default:
+		warnings = ", " + strconv.Itoa(w.warnings) + " warnings"
+	}
 	var format string
 	switch {
 	case w.affected:
 		// Exec query like CREATE VIEW.
-		format = "Query OK, %d rows affected (%.3f sec)\n\n"
+		format = "Query OK, %d rows affected%s (%.3f sec)\n\n"
 		fallthrough
 	case w.size > 0:
 		if format == "" {
@@ -111,12 +200,12 @@ func (w *StatsWriter) Flush() {
 				// Manages the plural :)
 				format += "s"
 			}
-			format += " in set (%.3f sec)\n\n"
+			format += " in set%s (%.3f sec)\n\n"
 		}
-		w.w.WriteString(fmt.Sprintf(format, w.size, w.t1.Sub(w.t0).Seconds()))
+		w.w.WriteString(fmt.Sprintf(format, w.size, warnings, w.t1.Sub(w.t0).Seconds()))
 	default:
-		format = "Empty set (%.3f sec)\n\n"
-		w.w.WriteString(fmt.Sprintf(format, w.t1.Sub(w.t0).Seconds()))
+		format = "Empty set%s (%.3f sec)\n\n"
+		w.w.WriteString(fmt.Sprintf(format, warnings, w.t1.Sub(w.t0).Seconds()))
 	}
 	w.w.Flush()
 }
@@ -126,6 +215,11 @@ func (w *StatsWriter) Position() int {
 	return w.size + 1
 }
 
+// Warn defines the number of warnings raised by the statement.
+func (w *StatsWriter) Warn(n int) {
+	w.warnings = n
+}
+
 // Write increments the number of written lines.
 func (w *StatsWriter) Write(record []string) error {
 	// Increments the number of rows in the result set.
@@ -143,19 +237,21 @@ func (w *StatsWriter) WriteHead(record []string) error {
 }
 
 // ASCIIWriter represents a terminal tables's writer.
+// It buffers the records in order to compute the size of each column.
 type ASCIIWriter struct {
-	w        *bufio.Writer
-	s        PositionWriter
-	fmt, sep string
+	w     *bufio.Writer
+	s     PositionWriter
+	head  []string
+	data  [][]string
+	foot  []string
+	sizes []int
 }
 
 // NewASCIIWriter returns a writer of term tables.
 func NewASCIIWriter(w io.Writer) Writer {
 	return &ASCIIWriter{
-		w:   bufio.NewWriter(w),
-		s:   NewStatsWriter(w, false),
-		fmt: asciiBorderY,
-		sep: asciiBorderI,
+		w: bufio.NewWriter(w),
+		s: NewStatsWriter(w, false),
 	}
 }
 
@@ -164,12 +260,41 @@ func (w *ASCIIWriter) Error() error {
 	return w.s.Error()
 }
 
-// Flush writes any buffered data to the underlying writer.
+// Flush writes the terminal table to the underlying writer.
 func (w *ASCIIWriter) Flush() {
-	// Outputs the terminal table.
-	if w.s.Position() > 1 {
-		// Prints the end of the table only if it contains at less one line.
-		fmt.Fprint(w.w, w.sep)
+	// Defines the format to use as separator line for a column.
+	var fmtColumn = func(size int, end string) string {
+		return " %-" + strconv.Itoa(size) + "v" + end
+	}
+	if w.head != nil {
+		// Builds the formats to use to display each line and to separate the records.
+		format, sep := asciiBorderY, asciiBorderI
+		for _, size := range w.sizes {
+			// Columns are surrounded by space.
+			format += fmtColumn(size+1, asciiBorderY)
+			sep += strings.Repeat(asciiBorderX, size+2) + asciiBorderI
+		}
+		format += "\n"
+		sep += "\n"
+
+		// Prints the table's head.
+		fmt.Fprint(w.w, sep)
+		fmt.Fprintf(w.w, format, toInterfaces(w.head)...)
+		fmt.Fprint(w.w, sep)
+
+		// Prints the records.
+		for _, record := range w.data {
+			fmt.Fprintf(w.w, format, toInterfaces(record)...)
+		}
+		if len(w.data) > 0 {
+			// Prints the end of the table only if it contains at less one line.
+			fmt.Fprint(w.w, sep)
+		}
+		if w.foot != nil {
+			// Prints the footer after the records, as a separated line.
+			fmt.Fprintf(w.w, format, toInterfaces(w.foot)...)
+			fmt.Fprint(w.w, sep)
+		}
 	}
 	// Writes any buffered data.
 	w.w.Flush()
@@ -177,45 +302,47 @@ func (w *ASCIIWriter) Flush() {
 	w.s.Flush()
 }
 
+// Warn defines the number of warnings raised by the statement.
+func (w *ASCIIWriter) Warn(n int) {
+	w.s.Warn(n)
+}
+
 // Write adds a line to the table.
 func (w *ASCIIWriter) Write(record []string) error {
-	// Prints the records
-	data := make([]interface{}, len(record))
+	line := make([]string, len(record))
 	for i, v := range record {
-		data[i] = v
+		line[i] = v
+		// Keeps the maximum length of each column.
+		if size := utf8.RuneCountInString(v); i < len(w.sizes) && size > w.sizes[i] {
+			w.sizes[i] = size
+		}
 	}
-	fmt.Fprintf(w.w, w.fmt, data...)
+	w.data = append(w.data, line)
 
 	return w.s.Write(record)
 }
 
-// WriteHead writes the table header and defines column sizes.
-func (w *ASCIIWriter) WriteHead(record []string) error {
-	// Defines the format to use as separator line for a column.
-	var fmtColumn = func(size int, end string) string {
-		return " %-" + strconv.Itoa(size) + "v" + end
-	}
-	// Builds the ascii table
-	data := make([]interface{}, len(record))
-	var size int
-	for i := range record {
-		// Converts string's slice to slice of interface.
-		data[i] = record[i]
-		// Defines the columns size surround by space.
-		size = utf8.RuneCountInString(record[i]) + 1
-		// Defines the format to use to display each line.
-		w.fmt += fmtColumn(size, asciiBorderY)
-		// Builds the line to separate each records
-		w.sep += strings.Repeat(asciiBorderX, size+1) + asciiBorderI
+// WriteFooter defines the last line of the table, as the totals of the columns.
+// It is not counted as a record.
+func (w *ASCIIWriter) WriteFooter(record []string) error {
+	w.foot = make([]string, len(record))
+	for i, v := range record {
+		w.foot[i] = v
+		if size := utf8.RuneCountInString(v); i < len(w.sizes) && size > w.sizes[i] {
+			w.sizes[i] = size
+		}
 	}
-	// Finalizes the formats.
-	w.fmt += "\n"
-	w.sep += "\n"
-	// Prints the table's head.
-	fmt.Fprintf(w.w, w.sep)
-	fmt.Fprintf(w.w, w.fmt, data...)
-	fmt.Fprintf(w.w, w.sep)
+	return nil
+}
 
+// WriteHead defines the table header and the default column sizes.
+func (w *ASCIIWriter) WriteHead(record []string) error {
+	w.head = make([]string, len(record))
+	w.sizes = make([]int, len(record))
+	for i, v := range record {
+		w.head[i] = strings.TrimSpace(v)
+		w.sizes[i] = utf8.RuneCountInString(w.head[i])
+	}
 	return w.s.WriteHead(record)
 }
 
@@ -248,6 +375,11 @@ func (w *VASCIIWriter) Flush() {
 	w.s.Flush()
 }
 
+// Warn defines the number of warnings raised by the statement.
+func (w *VASCIIWriter) Warn(n int) {
+	w.s.Warn(n)
+}
+
 // Write prints a new line for each column value.
 func (w *VASCIIWriter) Write(record []string) error {
 	// Prints the separator line.
@@ -278,3 +410,12 @@ func (w *VASCIIWriter) WriteHead(record []string) error {
 
 	return w.s.WriteHead(record)
 }
+
+// toInterfaces converts a slice of strings to a slice of interfaces.
+func toInterfaces(record []string) []interface{} {
+	data := make([]interface{}, len(record))
+	for i, v := range record {
+		data[i] = v
+	}
+	return data
+}